@@ -4,6 +4,8 @@
 package glider
 
 import (
+	"math"
+
 	mgl "github.com/go-gl/mathgl/mgl32"
 )
 
@@ -21,6 +23,17 @@ type AABSquare struct {
 	// Tags provides a way to label an AABB geometry in a custom application
 	// (e.g. labelling a collision as "wall" or "floor").
 	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// square is hit, such as the game object that owns it.
+	UserData interface{}
+
+	// Material describes the surface of the square.
+	Material Material
 }
 
 // NewAABSquare creates a new AABSquare object
@@ -40,6 +53,21 @@ func (aabs *AABSquare) IntersectPoint(v *mgl.Vec2) bool {
 	return true
 }
 
+// Validate checks the AABSquare for non-finite values and a Min corner
+// that is greater than the Max corner.
+func (aabs *AABSquare) Validate() error {
+	if err := validateFinite("AABSquare", "Min", aabs.Min[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("AABSquare", "Max", aabs.Max[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("AABSquare", "Offset", aabs.Offset[:]); err != nil {
+		return err
+	}
+	return validateBounds("AABSquare", aabs.Min[:], aabs.Max[:])
+}
+
 // AABBox is a axis aligned cube shape defined by a minimum and maximum corner.
 type AABBox struct {
 	// Min is the corner of the box opposite of Max. (e.g. lower-back-left corner)
@@ -56,6 +84,17 @@ type AABBox struct {
 	// Tags provides a way to label an AABB geometry in a custom application
 	// (e.g. labelling a collision as "wall" or "floor").
 	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// box is hit, such as the game object that owns it.
+	UserData interface{}
+
+	// Material describes the surface of the box.
+	Material Material
 }
 
 // NewAABBox creates a new AABBox object
@@ -76,6 +115,21 @@ func (aabb *AABBox) SetOffset3f(x, y, z float32) {
 	aabb.Offset[2] = z
 }
 
+// Validate checks the AABBox for non-finite values and a Min corner
+// that is greater than the Max corner.
+func (aabb *AABBox) Validate() error {
+	if err := validateFinite("AABBox", "Min", aabb.Min[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("AABBox", "Max", aabb.Max[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("AABBox", "Offset", aabb.Offset[:]); err != nil {
+		return err
+	}
+	return validateBounds("AABBox", aabb.Min[:], aabb.Max[:])
+}
+
 // IntersectPoint tests to see if the point is intersects the AABBox.
 func (aabb *AABBox) IntersectPoint(v *mgl.Vec3) bool {
 	aMinX := aabb.Min[0] + aabb.Offset[0]
@@ -126,25 +180,41 @@ func (aabb *AABBox) CollideVsAABBox(b2 *AABBox) int {
 	}
 }
 
-// CollideVsRay tests to see if a raycast intersects the AABBox.
+// CollideVsRay tests to see if a raycast intersects the AABBox. If the ray
+// starts inside the box the distance returned will be negative. Rays that
+// are parallel to an axis are handled explicitly so that origins lying on
+// a face of the box don't produce NaNs, and invalid rays never intersect.
 func (aabb *AABBox) CollideVsRay(ray *CollisionRay) (int, float32) {
-	aMinX := aabb.Min[0] + aabb.Offset[0]
-	aMinY := aabb.Min[1] + aabb.Offset[1]
-	aMinZ := aabb.Min[2] + aabb.Offset[2]
-	aMaxX := aabb.Max[0] + aabb.Offset[0]
-	aMaxY := aabb.Max[1] + aabb.Offset[1]
-	aMaxZ := aabb.Max[2] + aabb.Offset[2]
-
-	t1 := (aMinX - ray.Origin[0]) * ray.directionFraction[0]
-	t3 := (aMinY - ray.Origin[1]) * ray.directionFraction[1]
-	t5 := (aMinZ - ray.Origin[2]) * ray.directionFraction[2]
-
-	t2 := (aMaxX - ray.Origin[0]) * ray.directionFraction[0]
-	t4 := (aMaxY - ray.Origin[1]) * ray.directionFraction[1]
-	t6 := (aMaxZ - ray.Origin[2]) * ray.directionFraction[2]
+	if !ray.valid() {
+		return NoIntersect, 0.0
+	}
 
-	tmin := max32(max32(min32(t1, t2), min32(t3, t4)), min32(t5, t6))
-	tmax := min32(min32(max32(t1, t2), max32(t3, t4)), max32(t5, t6))
+	min := aabb.Min.Add(aabb.Offset)
+	max := aabb.Max.Add(aabb.Offset)
+
+	tmin := float32(math.Inf(-1))
+	tmax := float32(math.Inf(1))
+	for i := 0; i < 3; i++ {
+		// a ray parallel to this slab either runs between its planes or misses
+		if ray.direction[i] == 0 {
+			if ray.Origin[i] < min[i] || ray.Origin[i] > max[i] {
+				return NoIntersect, tmax
+			}
+			continue
+		}
+
+		t1 := (min[i] - ray.Origin[i]) * ray.directionFraction[i]
+		t2 := (max[i] - ray.Origin[i]) * ray.directionFraction[i]
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tmin {
+			tmin = t1
+		}
+		if t2 < tmax {
+			tmax = t2
+		}
+	}
 
 	// if tmax < 0, ray is intersecting the box, but the whole AABB is behind
 	if tmax < 0 {
@@ -155,6 +225,11 @@ func (aabb *AABBox) CollideVsRay(ray *CollisionRay) (int, float32) {
 		return NoIntersect, tmax
 	}
 
+	// if the box is further away than the end of the ray, it isn't hit
+	if !ray.inRange(tmin) {
+		return NoIntersect, tmin
+	}
+
 	return Intersect, tmin
 }
 
@@ -263,3 +338,78 @@ func (aabb *AABBox) CollideVsSphere(s *Sphere) int {
 
 	return NoIntersect
 }
+
+// ClassifyVsAABBox returns Inside if the AABBox is completely inside b2,
+// Outside if they don't touch and Intersecting otherwise.
+func (aabb *AABBox) ClassifyVsAABBox(b2 *AABBox) Classification {
+	if aabb.CollideVsAABBox(b2) == NoIntersect {
+		return Outside
+	}
+
+	aMin := aabb.Min.Add(aabb.Offset)
+	aMax := aabb.Max.Add(aabb.Offset)
+	bMin := b2.Min.Add(b2.Offset)
+	bMax := b2.Max.Add(b2.Offset)
+	for i := 0; i < 3; i++ {
+		if aMin[i] < bMin[i] || aMax[i] > bMax[i] {
+			return Intersecting
+		}
+	}
+	return Inside
+}
+
+// ClassifyVsSphere returns Inside if the AABBox is completely inside the
+// sphere, Outside if they don't touch and Intersecting otherwise.
+func (aabb *AABBox) ClassifyVsSphere(s *Sphere) Classification {
+	if aabb.CollideVsSphere(s) == NoIntersect {
+		return Outside
+	}
+
+	// the box is inside if the corner furthest from the sphere's center is
+	min := aabb.Min.Add(aabb.Offset)
+	max := aabb.Max.Add(aabb.Offset)
+	center := s.Center.Add(s.Offset)
+	var far mgl.Vec3
+	for i := 0; i < 3; i++ {
+		far[i] = max32(fabs32(min[i]-center[i]), fabs32(max[i]-center[i]))
+	}
+	if far.Dot(far) <= s.Radius*s.Radius {
+		return Inside
+	}
+	return Intersecting
+}
+
+// ClassifyVsPlane returns Front if the AABBox is completely in front of the
+// plane, Back if it's completely behind it and Straddling otherwise.
+func (aabb *AABBox) ClassifyVsPlane(p *Plane) Classification {
+	min := aabb.Min.Add(aabb.Offset)
+	max := aabb.Max.Add(aabb.Offset)
+	center := min.Add(max).Mul(0.5)
+	half := max.Sub(min).Mul(0.5)
+
+	// project the half sizes onto the plane normal
+	radius := half[0]*fabs32(p.Normal[0]) + half[1]*fabs32(p.Normal[1]) + half[2]*fabs32(p.Normal[2])
+	return classifyExtentVsPlane(p.Distance(center), radius)
+}
+
+// ClassifyVsFrustum returns Inside if the AABBox is completely inside the
+// frustum, Outside if it's completely outside of one of the frustum's planes
+// and Intersecting otherwise. Like most frustum tests, boxes near the
+// corners of the frustum may be reported as Intersecting when they are
+// actually outside.
+func (aabb *AABBox) ClassifyVsFrustum(f *Frustum) Classification {
+	return f.classify(aabb.ClassifyVsPlane)
+}
+
+// support returns the corner of the box furthest along d.
+func (aabb *AABBox) support(d mgl.Vec3) mgl.Vec3 {
+	var p mgl.Vec3
+	for i := 0; i < 3; i++ {
+		if d[i] >= 0 {
+			p[i] = aabb.Max[i] + aabb.Offset[i]
+		} else {
+			p[i] = aabb.Min[i] + aabb.Offset[i]
+		}
+	}
+	return p
+}
@@ -4,6 +4,7 @@
 package glider
 
 import (
+	"errors"
 	"testing"
 
 	mgl "github.com/go-gl/mathgl/mgl32"
@@ -330,3 +331,114 @@ func TestAABBoxCollisionVsSphere(t *testing.T) {
 	}
 
 }
+
+func TestAABBoxCollisionVsRaySegment(t *testing.T) {
+	var b1 AABBox
+	var r1 CollisionRay
+
+	b1.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	b1.Max = mgl.Vec3{1.0, 1.0, 1.0}
+	b1.Offset = mgl.Vec3{10.0, 0.0, 0.0}
+
+	// segment ends before the box
+	r1.SetSegment(mgl.Vec3{0.0, 0.0, 0.0}, mgl.Vec3{5.0, 0.0, 0.0})
+	intersect, _ := b1.CollideVsRay(&r1)
+	if intersect == Intersect {
+		t.Error("AABBox.IntersectRay() indicated true with a segment that ends before the box.")
+	}
+
+	// segment ends inside the box
+	r1.SetSegment(mgl.Vec3{0.0, 0.0, 0.0}, mgl.Vec3{9.5, 0.0, 0.0})
+	intersect, dist := b1.CollideVsRay(&r1)
+	if intersect == NoIntersect {
+		t.Error("AABBox.IntersectRay() indicated false with a segment that ends inside the box.")
+	}
+	if !mgl.FloatEqual(dist, 9.0) {
+		t.Errorf("AABBox.IntersectRay() returned the wrong distance: %f", dist)
+	}
+
+	// unbounded again
+	r1.MaxDistance = 0.0
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+	intersect, _ = b1.CollideVsRay(&r1)
+	if intersect == NoIntersect {
+		t.Error("AABBox.IntersectRay() indicated false with an unbounded ray pointed at it's center.")
+	}
+}
+
+func TestAABBoxCollisionVsAxisParallelRay(t *testing.T) {
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	b1.Max = mgl.Vec3{1.0, 1.0, 1.0}
+
+	// origin lies on the plane of the max y slab
+	r1, err := NewCollisionRay(mgl.Vec3{5.0, 1.0, 0.0}, mgl.Vec3{-1.0, 0.0, 0.0})
+	if err != nil {
+		t.Fatalf("NewCollisionRay() returned an error for a valid ray: %v", err)
+	}
+	intersect, dist := b1.CollideVsRay(r1)
+	if intersect != Intersect || dist != 4.0 {
+		t.Errorf("AABBox.IntersectRay() didn't hit with a ray along a face: %d, %f", intersect, dist)
+	}
+
+	// origin lies on the plane of the min x slab and runs along it
+	r1.Origin = mgl.Vec3{-1.0, 5.0, 0.0}
+	r1.SetDirection(mgl.Vec3{0.0, -1.0, 0.0})
+	intersect, dist = b1.CollideVsRay(r1)
+	if intersect != Intersect || dist != 4.0 {
+		t.Errorf("AABBox.IntersectRay() didn't hit with a ray along a face: %d, %f", intersect, dist)
+	}
+
+	// just outside of the slab
+	r1.Origin = mgl.Vec3{-1.001, 5.0, 0.0}
+	intersect, _ = b1.CollideVsRay(r1)
+	if intersect != NoIntersect {
+		t.Error("AABBox.IntersectRay() hit with a parallel ray outside of the box.")
+	}
+
+	// a direction component so small its reciprocal overflows
+	r1.Origin = mgl.Vec3{-1.0, 5.0, 0.0}
+	r1.SetDirection(mgl.Vec3{1e-39, -1.0, 0.0})
+	intersect, dist = b1.CollideVsRay(r1)
+	if intersect != Intersect || dist != 4.0 {
+		t.Errorf("AABBox.IntersectRay() didn't hit with a nearly parallel ray along a face: %d, %f", intersect, dist)
+	}
+}
+
+func TestAABBoxCollisionVsInvalidRay(t *testing.T) {
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	b1.Max = mgl.Vec3{1.0, 1.0, 1.0}
+
+	_, err := NewCollisionRay(mgl.Vec3{0.0, 0.0, 0.0}, mgl.Vec3{0.0, 0.0, 0.0})
+	if !errors.Is(err, ErrInvalidRay) {
+		t.Errorf("NewCollisionRay() didn't return ErrInvalidRay for a zero direction: %v", err)
+	}
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{0.0, 0.0, 0.0})
+	if !errors.Is(r1.Validate(), ErrInvalidRay) {
+		t.Error("CollisionRay.Validate() didn't report a zero direction.")
+	}
+	intersect, dist := b1.CollideVsRay(&r1)
+	if intersect != NoIntersect || dist != 0.0 {
+		t.Errorf("AABBox.IntersectRay() didn't reject a ray without a direction: %d, %f", intersect, dist)
+	}
+
+	// a segment with no length
+	r1.SetSegment(mgl.Vec3{0.0, 0.0, 0.0}, mgl.Vec3{0.0, 0.0, 0.0})
+	if !errors.Is(r1.Validate(), ErrInvalidRay) {
+		t.Error("CollisionRay.Validate() didn't report a zero length segment.")
+	}
+
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+	r1.MaxDistance = -1.0
+	if !errors.Is(r1.Validate(), ErrInvalidRay) {
+		t.Error("CollisionRay.Validate() didn't report a negative MaxDistance.")
+	}
+	r1.MaxDistance = 0.0
+	if r1.Validate() != nil {
+		t.Error("CollisionRay.Validate() reported a valid ray as invalid.")
+	}
+}
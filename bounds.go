@@ -0,0 +1,137 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// Bounds is a world-space axis aligned bounding volume used by broadphases
+// to quickly reject pairs of colliders that can't be touching.
+type Bounds struct {
+	// Min is the corner of the bounds opposite of Max.
+	Min mgl.Vec3
+
+	// Max is the corner of the bounds opposite of Min.
+	Max mgl.Vec3
+}
+
+// infiniteBounds covers all of space and is used for colliders whose size
+// isn't known.
+var infiniteBounds = Bounds{
+	Min: mgl.Vec3{float32(math.Inf(-1)), float32(math.Inf(-1)), float32(math.Inf(-1))},
+	Max: mgl.Vec3{float32(math.Inf(1)), float32(math.Inf(1)), float32(math.Inf(1))},
+}
+
+// Overlaps returns true if the two bounds touch.
+func (b Bounds) Overlaps(o Bounds) bool {
+	for i := 0; i < 3; i++ {
+		if b.Max[i] < o.Min[i] || b.Min[i] > o.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsPoint returns true if the point is inside the bounds.
+func (b Bounds) ContainsPoint(p mgl.Vec3) bool {
+	for i := 0; i < 3; i++ {
+		if p[i] < b.Min[i] || p[i] > b.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Expand returns the bounds grown to include the point.
+func (b Bounds) Expand(p mgl.Vec3) Bounds {
+	for i := 0; i < 3; i++ {
+		b.Min[i] = min32(b.Min[i], p[i])
+		b.Max[i] = max32(b.Max[i], p[i])
+	}
+	return b
+}
+
+// ClosestPoint returns the point inside the bounds that is closest to p.
+func (b Bounds) ClosestPoint(p mgl.Vec3) mgl.Vec3 {
+	for i := 0; i < 3; i++ {
+		p[i] = min32(max32(p[i], b.Min[i]), b.Max[i])
+	}
+	return p
+}
+
+// boundsFromPoints returns the bounds of all of the points.
+func boundsFromPoints(points ...mgl.Vec3) Bounds {
+	b := Bounds{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		b = b.Expand(p)
+	}
+	return b
+}
+
+// boundsFromSupport returns the bounds of a convex shape using its support
+// points along each axis.
+func boundsFromSupport(s convexShape) Bounds {
+	var b Bounds
+	for i := 0; i < 3; i++ {
+		var d mgl.Vec3
+		d[i] = 1
+		b.Max[i] = s.support(d)[i]
+		d[i] = -1
+		b.Min[i] = s.support(d)[i]
+	}
+	return b
+}
+
+// ColliderBounds returns the world-space bounds of any of the colliders in
+// this package. Colliders it doesn't know about get bounds that cover all
+// of space so that they are never rejected by a broadphase.
+func ColliderBounds(c Collider) Bounds {
+	switch s := c.(type) {
+	case *AABBox:
+		return Bounds{Min: s.Min.Add(s.Offset), Max: s.Max.Add(s.Offset)}
+	case *Sphere:
+		center := s.Center.Add(s.Offset)
+		r := mgl.Vec3{s.Radius, s.Radius, s.Radius}
+		return Bounds{Min: center.Sub(r), Max: center.Add(r)}
+	case *Triangle:
+		v0, v1, v2 := s.vertices()
+		return boundsFromPoints(v0, v1, v2)
+	case *Mesh:
+		if len(s.Vertices) == 0 {
+			return Bounds{Min: s.Offset, Max: s.Offset}
+		}
+		b := Bounds{Min: s.Vertices[0].Add(s.Offset), Max: s.Vertices[0].Add(s.Offset)}
+		for _, v := range s.Vertices[1:] {
+			b = b.Expand(v.Add(s.Offset))
+		}
+		return b
+	case *Ellipsoid:
+		return boundsFromSupport(s)
+	case *Cylinder:
+		return boundsFromSupport(s)
+	case *Cone:
+		return boundsFromSupport(s)
+	case *Heightfield:
+		b := Bounds{Min: s.Offset, Max: s.Offset}
+		if s.Width < 2 || s.Depth < 2 {
+			return b
+		}
+		b.Max[0] += float32(s.cellsX()) * s.Spacing
+		b.Max[2] += float32(s.cellsZ()) * s.Spacing
+		b.Min[1] = float32(math.Inf(1))
+		b.Max[1] = float32(math.Inf(-1))
+		for _, h := range s.Heights {
+			b.Min[1] = min32(b.Min[1], s.Offset[1]+h)
+			b.Max[1] = max32(b.Max[1], s.Offset[1]+h)
+		}
+		return b
+	case *VoxelGrid:
+		size := mgl.Vec3{float32(s.Width), float32(s.Height), float32(s.Depth)}.Mul(s.CellSize)
+		return Bounds{Min: s.Offset, Max: s.Offset.Add(size)}
+	}
+	return infiniteBounds
+}
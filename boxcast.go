@@ -0,0 +1,101 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+// boxCastShape returns a copy of the box placed with its Offset at the ray's
+// origin and its bounds around the origin.
+func boxCastShape(ray *CollisionRay, b *AABBox) (*AABBox, Bounds) {
+	moved := *b
+	moved.Offset = ray.Origin
+	return &moved, Bounds{Min: b.Min, Max: b.Max}
+}
+
+// obbCastShape returns a copy of the oriented box placed with its Offset at
+// the ray's origin and its bounds around the origin.
+func obbCastShape(ray *CollisionRay, obb *OBBox) (*OBBox, Bounds) {
+	moved := *obb
+	moved.orientation = orientationOrIdent(moved.orientation)
+	moved.transform = moved.orientation.Mat4()
+	moved.Offset = ray.Origin
+	moved.syncOffset()
+
+	corners := moved.Corners()
+	extent := boundsFromPoints(corners[:]...)
+	extent.Min = extent.Min.Sub(ray.Origin)
+	extent.Max = extent.Max.Sub(ray.Origin)
+	return &moved, extent
+}
+
+// BoxCast sweeps the box along the ray and returns where it first touches
+// the collider. The box is placed with its Offset at the ray's origin, so
+// its Min and Max are relative to the origin. Boxes, spheres, triangles,
+// meshes, heightfields, voxel grids, ellipsoids, cylinders and cones are
+// supported; other colliders are never hit.
+func BoxCast(ray *CollisionRay, b *AABBox, c Collider) (int, CastHit) {
+	shape, extent := boxCastShape(ray, b)
+	return castShape(ray, shape, extent, c)
+}
+
+// BoxCastVsOBBox sweeps the box along the ray and returns where it first
+// touches the oriented box. The box is placed with its Offset at the ray's
+// origin.
+func BoxCastVsOBBox(ray *CollisionRay, b *AABBox, obb *OBBox) (int, CastHit) {
+	shape, _ := boxCastShape(ray, b)
+	return castConvex(ray, castAlong(ray, shape), 0, obb)
+}
+
+// BoxCastVsPlane sweeps the box along the ray and returns where it first
+// touches the plane. The box is placed with its Offset at the ray's origin.
+// Like Plane.CollideVsRay, the plane can be hit from either side.
+func BoxCastVsPlane(ray *CollisionRay, b *AABBox, p *Plane) (int, CastHit) {
+	shape, _ := boxCastShape(ray, b)
+	return castVsPlane(ray, shape, 0, p)
+}
+
+// BoxCastFirst sweeps the box along the ray against all of the colliders
+// and returns the first one it touches. Colliders whose TagSet doesn't pass
+// all of the filters are skipped. The second return value is false if
+// nothing was hit.
+func BoxCastFirst(ray *CollisionRay, b *AABBox, colliders []Collider, filters ...TagFilter) (CastHit, bool) {
+	shape, extent := boxCastShape(ray, b)
+	return castFirst(colliders, filters, func(c Collider) (int, CastHit) {
+		return castShape(ray, shape, extent, c)
+	})
+}
+
+// OBBoxCast sweeps the oriented box along the ray and returns where it first
+// touches the collider. The box is placed with its Offset at the ray's
+// origin and keeps its orientation. The same colliders as BoxCast are
+// supported.
+func OBBoxCast(ray *CollisionRay, obb *OBBox, c Collider) (int, CastHit) {
+	shape, extent := obbCastShape(ray, obb)
+	return castShape(ray, shape, extent, c)
+}
+
+// OBBoxCastVsOBBox sweeps the oriented box along the ray and returns where
+// it first touches the other oriented box. The box is placed with its Offset
+// at the ray's origin.
+func OBBoxCastVsOBBox(ray *CollisionRay, obb *OBBox, other *OBBox) (int, CastHit) {
+	shape, _ := obbCastShape(ray, obb)
+	return castConvex(ray, castAlong(ray, shape), 0, other)
+}
+
+// OBBoxCastVsPlane sweeps the oriented box along the ray and returns where
+// it first touches the plane. The box is placed with its Offset at the ray's
+// origin. Like Plane.CollideVsRay, the plane can be hit from either side.
+func OBBoxCastVsPlane(ray *CollisionRay, obb *OBBox, p *Plane) (int, CastHit) {
+	shape, _ := obbCastShape(ray, obb)
+	return castVsPlane(ray, shape, 0, p)
+}
+
+// OBBoxCastFirst sweeps the oriented box along the ray against all of the
+// colliders and returns the first one it touches. Colliders whose TagSet
+// doesn't pass all of the filters are skipped. The second return value is
+// false if nothing was hit.
+func OBBoxCastFirst(ray *CollisionRay, obb *OBBox, colliders []Collider, filters ...TagFilter) (CastHit, bool) {
+	shape, extent := obbCastShape(ray, obb)
+	return castFirst(colliders, filters, func(c Collider) (int, CastHit) {
+		return castShape(ray, shape, extent, c)
+	})
+}
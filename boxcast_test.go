@@ -0,0 +1,178 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBoxCastVsShapes(t *testing.T) {
+	// a unit box moving down the -z axis
+	ray, _ := NewCollisionRay(mgl.Vec3{0.0, 0.0, 5.0}, mgl.Vec3{0.0, 0.0, -1.0})
+	cast := newTestWorldBox(0, 0, 0)
+	box := newTestWorldBox(0, 0, 0)
+	result, hit := BoxCast(ray, cast, box)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 4.0, 1e-3) {
+		t.Errorf("BoxCast() returned %v, %v instead of hitting at 4.0", result, hit.Distance)
+	}
+	if !hit.Normal.ApproxEqualThreshold(mgl.Vec3{0, 0, 1}, 1e-3) || !mgl.FloatEqualThreshold(hit.Point[2], 0.5, 1e-3) {
+		t.Errorf("BoxCast() returned the point %v and normal %v", hit.Point, hit.Normal)
+	}
+	if hit.Collider != box {
+		t.Error("BoxCast() didn't set the collider that was hit.")
+	}
+	if cast.Offset != (mgl.Vec3{}) {
+		t.Error("BoxCast() moved the box being cast.")
+	}
+
+	// moving past the box with a small gap between them
+	passing, _ := NewCollisionRay(mgl.Vec3{1.1, 0.0, 5.0}, mgl.Vec3{0.0, 0.0, -1.0})
+	if result, _ = BoxCast(passing, cast, box); result != NoIntersect {
+		t.Error("BoxCast() hit a box it should have passed by.")
+	}
+	ray.MaxDistance = 3.0
+	if result, _ = BoxCast(ray, cast, box); result != NoIntersect {
+		t.Error("BoxCast() hit a box past the ray's max distance.")
+	}
+	ray.MaxDistance = 0.0
+
+	s := Sphere{Radius: 1.0}
+	result, hit = BoxCast(ray, cast, &s)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 3.5, 1e-3) {
+		t.Errorf("BoxCast() returned %v, %v instead of hitting a sphere at 3.5", result, hit.Distance)
+	}
+
+	// an oriented box turned so that a corner leads the way
+	obb := NewOBBox()
+	obb.HalfSize = mgl.Vec3{0.5, 0.5, 0.5}
+	obb.SetOrientation(mgl.QuatRotate(mgl.DegToRad(45.0), mgl.Vec3{0, 1, 0}))
+	corner := float32(0.7071068)
+	result, hit = OBBoxCast(ray, obb, box)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 5.0-corner-0.5, 1e-3) {
+		t.Errorf("OBBoxCast() returned %v, %v instead of hitting with its corner", result, hit.Distance)
+	}
+	result, hit = BoxCastVsOBBox(ray, cast, obb)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 5.0-corner-0.5, 1e-3) {
+		t.Errorf("BoxCastVsOBBox() returned %v, %v instead of hitting the corner", result, hit.Distance)
+	}
+	result, hit = OBBoxCastVsOBBox(ray, obb, obb)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 5.0-2*corner, 1e-3) {
+		t.Errorf("OBBoxCastVsOBBox() returned %v, %v instead of hitting corner to corner", result, hit.Distance)
+	}
+
+	// a zero value oriented box that wasn't made with NewOBBox
+	literal := &OBBox{HalfSize: mgl.Vec3{0.5, 0.5, 0.5}}
+	result, hit = OBBoxCast(ray, literal, box)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 4.0, 1e-3) {
+		t.Errorf("OBBoxCast() returned %v, %v for a zero value box instead of hitting at 4.0", result, hit.Distance)
+	}
+}
+
+func TestBoxCastVsPlane(t *testing.T) {
+	plane := NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 0, 0})
+	cast := newTestWorldBox(0, 0, 0)
+	ray, _ := NewCollisionRay(mgl.Vec3{0.0, 3.0, 0.0}, mgl.Vec3{1.0, -1.0, 0.0})
+	result, hit := BoxCastVsPlane(ray, cast, plane)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 2.5*1.4142135, 1e-3) {
+		t.Errorf("BoxCastVsPlane() returned %v, %v instead of hitting the plane", result, hit.Distance)
+	}
+	if !mgl.FloatEqualThreshold(hit.Point[1], 0.0, 1e-4) || !hit.Normal.ApproxEqualThreshold(mgl.Vec3{0, 1, 0}, 1e-4) {
+		t.Errorf("BoxCastVsPlane() returned the point %v and normal %v", hit.Point, hit.Normal)
+	}
+
+	// a box standing on one of its corners reaches further down
+	obb := NewOBBox()
+	obb.HalfSize = mgl.Vec3{0.5, 0.5, 0.5}
+	obb.SetOrientation(mgl.QuatRotate(mgl.DegToRad(45.0), mgl.Vec3{0, 0, 1}))
+	down, _ := NewCollisionRay(mgl.Vec3{0.0, 3.0, 0.0}, mgl.Vec3{0.0, -1.0, 0.0})
+	result, hit = OBBoxCastVsPlane(down, obb, plane)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 3.0-0.7071068, 1e-3) {
+		t.Errorf("OBBoxCastVsPlane() returned %v, %v instead of hitting with its corner", result, hit.Distance)
+	}
+
+	up, _ := NewCollisionRay(mgl.Vec3{0.0, 3.0, 0.0}, mgl.Vec3{0.0, 1.0, 0.0})
+	if result, _ = BoxCastVsPlane(up, cast, plane); result != NoIntersect {
+		t.Error("BoxCastVsPlane() hit a plane the box was moving away from.")
+	}
+}
+
+func TestBoxCastVsMeshAndVoxels(t *testing.T) {
+	vertices := []mgl.Vec3{
+		{-5.0, 0.0, -5.0}, {5.0, 0.0, -5.0}, {5.0, 0.0, 5.0}, {-5.0, 0.0, 5.0},
+	}
+	floor := NewMesh(vertices, []uint32{0, 2, 1, 0, 3, 2})
+	cast := newTestWorldBox(0, 0, 0)
+	ray, _ := NewCollisionRay(mgl.Vec3{1.0, 4.0, 1.0}, mgl.Vec3{0.0, -1.0, 0.0})
+	result, hit := BoxCast(ray, cast, floor)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 3.5, 1e-3) || hit.Normal.Sub(mgl.Vec3{0, 1, 0}).Len() > 1e-3 {
+		t.Errorf("BoxCast() returned %v, %v, %v instead of hitting the floor", result, hit.Distance, hit.Normal)
+	}
+
+	// a small box sliding over the voxel floor into the pillar
+	vg := newTestVoxelFloor()
+	small := NewAABBox()
+	small.Min = mgl.Vec3{-0.25, -0.25, -0.25}
+	small.Max = mgl.Vec3{0.25, 0.25, 0.25}
+	path, _ := NewCollisionRay(mgl.Vec3{1.5, 1.5, 4.5}, mgl.Vec3{1.0, 0.0, 0.0})
+	result, hit = BoxCast(path, small, vg)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 2.25, 1e-3) || hit.Normal.Sub(mgl.Vec3{-1, 0, 0}).Len() > 1e-3 {
+		t.Errorf("BoxCast() returned %v, %v, %v instead of hitting the pillar", result, hit.Distance, hit.Normal)
+	}
+	path.Origin = mgl.Vec3{1.5, 1.5, 2.5}
+	if result, _ = BoxCast(path, small, vg); result != NoIntersect {
+		t.Error("BoxCast() hit a voxel on a clear path.")
+	}
+}
+
+func TestBoxCastFirst(t *testing.T) {
+	colliders := []Collider{newTestWorldBox(0, 0, -5), newTestWorldBox(0, 0, -3), newTestWorldBox(3, 0, -1)}
+	cast := newTestWorldBox(0, 0, 0)
+	ray, _ := NewCollisionRay(mgl.Vec3{}, mgl.Vec3{0, 0, -1})
+	hit, okay := BoxCastFirst(ray, cast, colliders)
+	if !okay || hit.Collider != colliders[1] || !mgl.FloatEqualThreshold(hit.Distance, 2.0, 1e-3) {
+		t.Errorf("BoxCastFirst() returned %v, %v instead of the closest box", hit, okay)
+	}
+
+	// a segment that stops short of the boxes is clear
+	ray.SetSegment(mgl.Vec3{}, mgl.Vec3{0, 0, -1.5})
+	if _, okay = BoxCastFirst(ray, cast, colliders); okay {
+		t.Error("BoxCastFirst() hit a box on a clear segment.")
+	}
+
+	obb := NewOBBox()
+	obb.HalfSize = mgl.Vec3{0.5, 0.5, 0.5}
+	ray.SetSegment(mgl.Vec3{}, mgl.Vec3{0, 0, -10})
+	if hit, okay = OBBoxCastFirst(ray, obb, colliders); !okay || hit.Collider != colliders[1] {
+		t.Errorf("OBBoxCastFirst() returned %v, %v instead of the closest box", hit, okay)
+	}
+}
+
+func TestWorldBoxCast(t *testing.T) {
+	w := NewWorld(NewSweepAndPruneBroadphase())
+	near := w.Add(newTestWorldBox(0, 0, -5))
+	w.Add(newTestWorldBox(0, 0, -10))
+	side := w.Add(newTestWorldBox(1.5, 0, -2))
+
+	cast := newTestWorldBox(0, 0, 0)
+	ray, _ := NewCollisionRay(mgl.Vec3{}, mgl.Vec3{0, 0, -1})
+	hit, okay := w.BoxCast(ray, cast, AllLayers)
+	if !okay || hit.Handle != near || !mgl.FloatEqualThreshold(hit.Distance, 4.0, 1e-3) {
+		t.Errorf("World.BoxCast() returned %v, %v instead of the nearest box", hit, okay)
+	}
+
+	// a box wide enough to clip the one off to the side
+	cast.Max[0] = 1.2
+	if hit, okay = w.BoxCast(ray, cast, AllLayers); !okay || hit.Handle != side || !mgl.FloatEqualThreshold(hit.Distance, 1.0, 1e-3) {
+		t.Errorf("World.BoxCast() returned %v, %v instead of the box off to the side", hit, okay)
+	}
+
+	obb := NewOBBox()
+	obb.HalfSize = mgl.Vec3{0.5, 0.5, 0.5}
+	ray.MaxDistance = 3.0
+	if _, okay = w.OBBoxCast(ray, obb, AllLayers); okay {
+		t.Error("World.OBBoxCast() hit a box past the ray's max distance.")
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"sort"
+)
+
+// HandlePair identifies two colliders with the lower handle first.
+type HandlePair struct {
+	A, B Handle
+}
+
+// newHandlePair returns the pair with the lower handle first.
+func newHandlePair(a, b Handle) HandlePair {
+	if b < a {
+		a, b = b, a
+	}
+	return HandlePair{a, b}
+}
+
+// Broadphase is implemented by spatial structures that can quickly find
+// the colliders whose Bounds overlap so that the exact collision tests
+// only have to be run on those.
+type Broadphase interface {
+	// Insert adds the handle with its bounds.
+	Insert(h Handle, b Bounds)
+
+	// Update changes the bounds of a handle that was inserted.
+	Update(h Handle, b Bounds)
+
+	// Remove removes the handle.
+	Remove(h Handle)
+
+	// Pairs appends every pair of handles whose bounds overlap to pairs
+	// and returns the result.
+	Pairs(pairs []HandlePair) []HandlePair
+
+	// Query appends every handle whose bounds overlap b to result and
+	// returns the result.
+	Query(b Bounds, result []Handle) []Handle
+}
+
+// BroadphaseWalker is implemented by broadphases that can list the bounds
+// they hold, such as for drawing them while debugging.
+type BroadphaseWalker interface {
+	// WalkBounds calls fn with every handle and its bounds.
+	WalkBounds(fn func(h Handle, b Bounds))
+}
+
+// ParallelBroadphase is implemented by broadphases that can split finding
+// pairs across goroutines.
+type ParallelBroadphase interface {
+	Broadphase
+
+	// ParallelPairs appends the same pairs in the same order as Pairs to
+	// pairs using up to workers goroutines and returns the result.
+	ParallelPairs(workers int, pairs []HandlePair) []HandlePair
+}
+
+// broadphaseEntry is a handle and its bounds stored in a broadphase.
+type broadphaseEntry struct {
+	handle Handle
+	bounds Bounds
+}
+
+// broadphaseEntries is a list of entries with an index to find a handle's
+// entry quickly.
+type broadphaseEntries struct {
+	entries    []broadphaseEntry
+	index      map[Handle]int
+	chunkPairs [][]HandlePair
+}
+
+func (be *broadphaseEntries) insert(h Handle, b Bounds) {
+	if be.index == nil {
+		be.index = make(map[Handle]int)
+	}
+	if i, okay := be.index[h]; okay {
+		be.entries[i].bounds = b
+		return
+	}
+	be.index[h] = len(be.entries)
+	be.entries = append(be.entries, broadphaseEntry{h, b})
+}
+
+func (be *broadphaseEntries) update(h Handle, b Bounds) {
+	if i, okay := be.index[h]; okay {
+		be.entries[i].bounds = b
+	}
+}
+
+func (be *broadphaseEntries) remove(h Handle) {
+	i, okay := be.index[h]
+	if !okay {
+		return
+	}
+
+	// keep the entries in order so that results stay deterministic
+	delete(be.index, h)
+	be.entries = append(be.entries[:i], be.entries[i+1:]...)
+	for j := i; j < len(be.entries); j++ {
+		be.index[be.entries[j].handle] = j
+	}
+}
+
+func (be *broadphaseEntries) query(b Bounds, result []Handle) []Handle {
+	for _, e := range be.entries {
+		if e.bounds.Overlaps(b) {
+			result = append(result, e.handle)
+		}
+	}
+	return result
+}
+
+// pairsInRange appends the pairs of overlapping entries whose first entry
+// has an index from start up to but not including end. If sorted is true the
+// entries are sorted by the minimum of their bounds on the X axis and the
+// search for each entry's pairs stops at the first one starting after it ends.
+func (be *broadphaseEntries) pairsInRange(start, end int, sorted bool, pairs []HandlePair) []HandlePair {
+	entries := be.entries
+	for i := start; i < end; i++ {
+		e1 := entries[i]
+		for _, e2 := range entries[i+1:] {
+			// everything further along starts after this one ends
+			if sorted && e2.bounds.Min[0] > e1.bounds.Max[0] {
+				break
+			}
+			if e1.bounds.Overlaps(e2.bounds) {
+				pairs = append(pairs, newHandlePair(e1.handle, e2.handle))
+			}
+		}
+	}
+	return pairs
+}
+
+// parallelPairs is pairsInRange over all of the entries split across the
+// workers. Each chunk of entries gets its own buffer, which is kept for the
+// next call, and the buffers are appended to pairs in order so the result
+// is the same as pairsInRange's.
+func (be *broadphaseEntries) parallelPairs(workers int, sorted bool, pairs []HandlePair) []HandlePair {
+	n := len(be.entries)
+	chunks := chunkCount(n, workers)
+	for len(be.chunkPairs) < chunks {
+		be.chunkPairs = append(be.chunkPairs, nil)
+	}
+
+	runParallel(workers, chunks, func(c int) {
+		start, end := chunkRange(n, chunks, c)
+		be.chunkPairs[c] = be.pairsInRange(start, end, sorted, be.chunkPairs[c][:0])
+	})
+	for _, chunk := range be.chunkPairs[:chunks] {
+		pairs = append(pairs, chunk...)
+	}
+	return pairs
+}
+
+// WalkBounds calls fn with every handle and its bounds.
+func (be *broadphaseEntries) WalkBounds(fn func(h Handle, b Bounds)) {
+	for _, e := range be.entries {
+		fn(e.handle, e.bounds)
+	}
+}
+
+// BruteForceBroadphase tests the bounds of every pair of handles. It is
+// the simplest broadphase and is fast enough for small numbers of colliders.
+type BruteForceBroadphase struct {
+	broadphaseEntries
+}
+
+// NewBruteForceBroadphase creates a new, empty BruteForceBroadphase object.
+func NewBruteForceBroadphase() *BruteForceBroadphase {
+	return new(BruteForceBroadphase)
+}
+
+// Insert adds the handle with its bounds.
+func (bf *BruteForceBroadphase) Insert(h Handle, b Bounds) {
+	bf.insert(h, b)
+}
+
+// Update changes the bounds of a handle that was inserted.
+func (bf *BruteForceBroadphase) Update(h Handle, b Bounds) {
+	bf.update(h, b)
+}
+
+// Remove removes the handle.
+func (bf *BruteForceBroadphase) Remove(h Handle) {
+	bf.remove(h)
+}
+
+// Pairs appends every pair of handles whose bounds overlap to pairs and
+// returns the result.
+func (bf *BruteForceBroadphase) Pairs(pairs []HandlePair) []HandlePair {
+	return bf.pairsInRange(0, len(bf.entries), false, pairs)
+}
+
+// ParallelPairs is Pairs with the tests split across the workers.
+func (bf *BruteForceBroadphase) ParallelPairs(workers int, pairs []HandlePair) []HandlePair {
+	return bf.parallelPairs(workers, false, pairs)
+}
+
+// Query appends every handle whose bounds overlap b to result and returns
+// the result.
+func (bf *BruteForceBroadphase) Query(b Bounds, result []Handle) []Handle {
+	return bf.query(b, result)
+}
+
+// SweepAndPruneBroadphase keeps the handles sorted along the X axis so that
+// only handles whose bounds overlap on that axis need to be tested against
+// each other. Colliders usually move a little between steps so the sort is
+// cheap to keep up to date.
+type SweepAndPruneBroadphase struct {
+	broadphaseEntries
+}
+
+// NewSweepAndPruneBroadphase creates a new, empty SweepAndPruneBroadphase object.
+func NewSweepAndPruneBroadphase() *SweepAndPruneBroadphase {
+	return new(SweepAndPruneBroadphase)
+}
+
+// Insert adds the handle with its bounds.
+func (sap *SweepAndPruneBroadphase) Insert(h Handle, b Bounds) {
+	sap.insert(h, b)
+}
+
+// Update changes the bounds of a handle that was inserted.
+func (sap *SweepAndPruneBroadphase) Update(h Handle, b Bounds) {
+	sap.update(h, b)
+}
+
+// Remove removes the handle.
+func (sap *SweepAndPruneBroadphase) Remove(h Handle) {
+	sap.remove(h)
+}
+
+// sort orders the entries by the minimum of their bounds on the X axis.
+// An insertion sort is used because the entries are usually nearly sorted
+// from the last step.
+func (sap *SweepAndPruneBroadphase) sort() {
+	entries := sap.entries
+	for i := 1; i < len(entries); i++ {
+		e := entries[i]
+		j := i - 1
+		for j >= 0 && entries[j].bounds.Min[0] > e.bounds.Min[0] {
+			entries[j+1] = entries[j]
+			j--
+		}
+		entries[j+1] = e
+	}
+	for i, e := range entries {
+		sap.index[e.handle] = i
+	}
+}
+
+// Pairs appends every pair of handles whose bounds overlap to pairs and
+// returns the result.
+func (sap *SweepAndPruneBroadphase) Pairs(pairs []HandlePair) []HandlePair {
+	sap.sort()
+	return sap.pairsInRange(0, len(sap.entries), true, pairs)
+}
+
+// ParallelPairs is Pairs with the sweep split across the workers.
+func (sap *SweepAndPruneBroadphase) ParallelPairs(workers int, pairs []HandlePair) []HandlePair {
+	sap.sort()
+	return sap.parallelPairs(workers, true, pairs)
+}
+
+// Query appends every handle whose bounds overlap b to result and returns
+// the result.
+func (sap *SweepAndPruneBroadphase) Query(b Bounds, result []Handle) []Handle {
+	return sap.query(b, result)
+}
+
+// handlePairsByHandle sorts HandlePair slices by A and then B.
+type handlePairsByHandle []HandlePair
+
+func (pairs handlePairsByHandle) Len() int      { return len(pairs) }
+func (pairs handlePairsByHandle) Swap(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] }
+func (pairs handlePairsByHandle) Less(i, j int) bool {
+	if pairs[i].A != pairs[j].A {
+		return pairs[i].A < pairs[j].A
+	}
+	return pairs[i].B < pairs[j].B
+}
+
+// sortHandlePairs sorts the pairs so that results don't depend on the
+// broadphase used. It takes a pointer so that sorting a slice kept in a
+// struct doesn't allocate.
+func sortHandlePairs(pairs *[]HandlePair) {
+	sort.Sort((*handlePairsByHandle)(pairs))
+}
+
+// handlesByValue sorts Handle slices in increasing order.
+type handlesByValue []Handle
+
+func (handles handlesByValue) Len() int           { return len(handles) }
+func (handles handlesByValue) Swap(i, j int)      { handles[i], handles[j] = handles[j], handles[i] }
+func (handles handlesByValue) Less(i, j int) bool { return handles[i] < handles[j] }
+
+// sortHandles sorts the handles in increasing order. It takes a pointer so
+// that sorting a slice kept in a struct doesn't allocate.
+func sortHandles(handles *[]Handle) {
+	sort.Sort((*handlesByValue)(handles))
+}
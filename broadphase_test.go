@@ -0,0 +1,135 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBroadphasePairs(t *testing.T) {
+	broadphases := map[string]Broadphase{
+		"brute force":     NewBruteForceBroadphase(),
+		"sweep and prune": NewSweepAndPruneBroadphase(),
+	}
+
+	for name, bp := range broadphases {
+		// a row of unit boxes where each one touches the next
+		for i := 0; i < 5; i++ {
+			x := float32(4-i) * 0.9
+			bp.Insert(Handle(i+1), Bounds{Min: mgl.Vec3{x, 0, 0}, Max: mgl.Vec3{x + 1, 1, 1}})
+		}
+
+		pairs := bp.Pairs(nil)
+		sortHandlePairs(&pairs)
+		expected := []HandlePair{{1, 2}, {2, 3}, {3, 4}, {4, 5}}
+		if len(pairs) != len(expected) {
+			t.Errorf("%s: Pairs() returned %v instead of %v", name, pairs, expected)
+			continue
+		}
+		for i := range expected {
+			if pairs[i] != expected[i] {
+				t.Errorf("%s: Pairs() returned %v instead of %v", name, pairs, expected)
+				break
+			}
+		}
+
+		// move the last box away and take out the middle one
+		bp.Update(5, Bounds{Min: mgl.Vec3{10, 0, 0}, Max: mgl.Vec3{11, 1, 1}})
+		bp.Remove(3)
+		pairs = bp.Pairs(pairs[:0])
+		if len(pairs) != 1 || pairs[0] != (HandlePair{1, 2}) {
+			t.Errorf("%s: Pairs() returned %v after an update and remove", name, pairs)
+		}
+
+		handles := bp.Query(Bounds{Min: mgl.Vec3{10.5, 0.5, 0.5}, Max: mgl.Vec3{12, 2, 2}}, nil)
+		if len(handles) != 1 || handles[0] != 5 {
+			t.Errorf("%s: Query() returned %v instead of [5]", name, handles)
+		}
+	}
+}
+
+func TestColliderBounds(t *testing.T) {
+	s := NewSphere()
+	s.Radius = 2.0
+	s.SetOffset3f(1.0, 0.0, 0.0)
+	b := ColliderBounds(s)
+	if b.Min != (mgl.Vec3{-1, -2, -2}) || b.Max != (mgl.Vec3{3, 2, 2}) {
+		t.Errorf("ColliderBounds() returned the wrong bounds for a sphere: %v", b)
+	}
+
+	c := NewCylinder()
+	c.Radius = 1.0
+	c.HalfHeight = 3.0
+	c.SetOrientation(mgl.QuatRotate(mgl.DegToRad(90.0), mgl.Vec3{0, 0, 1}))
+	b = ColliderBounds(c)
+	if !b.Min.ApproxEqualThreshold(mgl.Vec3{-3, -1, -1}, 1e-4) || !b.Max.ApproxEqualThreshold(mgl.Vec3{3, 1, 1}, 1e-4) {
+		t.Errorf("ColliderBounds() returned the wrong bounds for a rotated cylinder: %v", b)
+	}
+
+	hf := newTestRampHeightfield()
+	b = ColliderBounds(hf)
+	if b.Min != (mgl.Vec3{0, 0, 0}) || b.Max != (mgl.Vec3{6, 3, 6}) {
+		t.Errorf("ColliderBounds() returned the wrong bounds for a heightfield: %v", b)
+	}
+}
+
+func TestBroadphasePairsAllocs(t *testing.T) {
+	broadphases := map[string]Broadphase{
+		"brute force":     NewBruteForceBroadphase(),
+		"sweep and prune": NewSweepAndPruneBroadphase(),
+	}
+
+	for name, bp := range broadphases {
+		for i := 0; i < 50; i++ {
+			x := float32(i%10) * 0.9
+			bp.Insert(Handle(i+1), Bounds{Min: mgl.Vec3{x, float32(i / 10), 0}, Max: mgl.Vec3{x + 1, float32(i/10) + 1, 1}})
+		}
+
+		pairs := bp.Pairs(nil)
+		handles := bp.Query(Bounds{Max: mgl.Vec3{5, 5, 5}}, nil)
+		allocs := testing.AllocsPerRun(100, func() {
+			pairs = bp.Pairs(pairs[:0])
+			sortHandlePairs(&pairs)
+			handles = bp.Query(Bounds{Max: mgl.Vec3{5, 5, 5}}, handles[:0])
+		})
+		if allocs != 0 {
+			t.Errorf("%s: Pairs() and Query() allocated %v times with a buffer", name, allocs)
+		}
+	}
+}
+
+func TestBroadphaseParallelPairs(t *testing.T) {
+	broadphases := map[string]ParallelBroadphase{
+		"brute force":     NewBruteForceBroadphase(),
+		"sweep and prune": NewSweepAndPruneBroadphase(),
+	}
+
+	for name, bp := range broadphases {
+		_, soa := newTestSoABoxes(300)
+		for i := 0; i < soa.Len(); i++ {
+			min, max := soa.Get(i)
+			bp.Insert(Handle(i+1), Bounds{Min: min, Max: max})
+		}
+
+		expected := bp.Pairs(nil)
+		if len(expected) == 0 {
+			t.Fatalf("%s: Pairs() didn't find any pairs to compare", name)
+		}
+		for _, workers := range []int{1, 2, 3, 8, 1000} {
+			pairs := bp.ParallelPairs(workers, nil)
+			if len(pairs) != len(expected) {
+				t.Errorf("%s: ParallelPairs(%d) returned %d pairs instead of %d", name, workers, len(pairs), len(expected))
+				continue
+			}
+			for i := range pairs {
+				if pairs[i] != expected[i] {
+					t.Errorf("%s: ParallelPairs(%d) returned %v at %d instead of %v", name, workers, pairs[i], i, expected[i])
+					break
+				}
+			}
+		}
+	}
+}
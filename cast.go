@@ -0,0 +1,334 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// castMaxIterations limits how many times a shape cast will advance
+	// towards its target before giving up.
+	castMaxIterations = 64
+
+	// castTolerance is how close a cast shape has to get to its target to
+	// count as touching it. Shapes are advanced to within half of this so
+	// that they stay far enough apart for the hit normal to be found.
+	castTolerance = 1e-3
+)
+
+// CastHit describes where a shape swept along a ray first touched something.
+type CastHit struct {
+	// Collider is the object that was hit, if the cast was made against a
+	// set of colliders.
+	Collider Collider
+
+	// Handle is the handle of Collider when the cast was made through a World.
+	Handle Handle
+
+	// Distance is how far along the ray the shape moved before touching.
+	// Shapes that start out touching have a distance of zero.
+	Distance float32
+
+	// Point is the world-space point where the shapes touch.
+	Point mgl.Vec3
+
+	// Normal is the world-space surface normal of the object that was hit at
+	// Point, facing towards the cast shape. Shapes that start out touching
+	// get a normal facing back along the ray.
+	Normal mgl.Vec3
+}
+
+// castConvex sweeps the convex shape made by moving start along the ray
+// against the target using conservative advancement: the closest points
+// between the shapes give a distance that the cast shape can safely move
+// before it could possibly touch the target, so the shape is advanced that
+// far until it touches or is moving away. The cast function returns the
+// cast shape after it has moved the distance t, and margin is a radius
+// around it, such as the radius of a sphere cast around a point.
+func castConvex(ray *CollisionRay, cast func(t float32) convexShape, margin float32, target convexShape) (int, CastHit) {
+	var hit CastHit
+	if !ray.valid() {
+		return NoIntersect, hit
+	}
+
+	d := ray.direction
+	t := float32(0.0)
+	for i := 0; i < castMaxIterations; i++ {
+		dist, p1, p2 := gjkDistance(cast(t), target)
+		gap := dist - margin
+
+		// started out touching, or sunk in too far to know the normal
+		if dist == 0 || (t == 0 && gap <= castTolerance) {
+			hit.Distance = t
+			hit.Point = p2
+			hit.Normal = d.Mul(-1.0)
+			if !ray.inRange(t) {
+				return NoIntersect, CastHit{}
+			}
+			return Intersect, hit
+		}
+
+		n := p1.Sub(p2).Mul(1.0 / dist)
+		if gap <= castTolerance {
+			hit.Distance = t
+			hit.Point = p2
+			hit.Normal = n
+			return Intersect, hit
+		}
+
+		// the speed the gap is closing at; if it isn't then the shapes
+		// are separated by a plane the cast shape is moving away from
+		closing := -d.Dot(n)
+		if closing <= rayEpsilon {
+			return NoIntersect, hit
+		}
+		t += (gap - castTolerance*0.5) / closing
+		if !ray.inRange(t) {
+			return NoIntersect, hit
+		}
+	}
+
+	return NoIntersect, hit
+}
+
+// castVsPlane sweeps the convex shape, placed at the ray's origin, along the
+// ray against the plane. The plane can be hit from either side; the side the
+// ray's origin is on is the one that counts.
+func castVsPlane(ray *CollisionRay, shape convexShape, margin float32, p *Plane) (int, CastHit) {
+	var hit CastHit
+	if !ray.valid() {
+		return NoIntersect, hit
+	}
+
+	nLen := p.Normal.Len()
+	if nLen == 0 {
+		return NoIntersect, hit
+	}
+	n := p.Normal.Mul(1.0 / nLen)
+	side := float32(1.0)
+	if p.Distance(ray.Origin) < 0 {
+		n = n.Mul(-1.0)
+		side = -1.0
+	}
+
+	// the point on the shape closest to the plane
+	low := shape.support(n.Mul(-1.0))
+	lowDist := side * p.Distance(low) / nLen
+	if lowDist <= margin {
+		hit.Normal = ray.direction.Mul(-1.0)
+		hit.Point = low.Sub(n.Mul(lowDist))
+		return Intersect, hit
+	}
+
+	closing := -n.Dot(ray.direction)
+	if closing <= rayEpsilon {
+		return NoIntersect, hit
+	}
+	t := (lowDist - margin) / closing
+	if !ray.inRange(t) {
+		return NoIntersect, hit
+	}
+	hit.Distance = t
+	hit.Normal = n
+	hit.Point = low.Add(ray.direction.Mul(t)).Sub(n.Mul(margin))
+	return Intersect, hit
+}
+
+// sweptBounds returns the bounds covered by something with the bounds
+// extent around the ray's origin moving along the ray. Unbounded rays are
+// only followed as far as maxDist.
+func sweptBounds(ray *CollisionRay, extent Bounds, maxDist float32) Bounds {
+	if ray.MaxDistance > 0 {
+		maxDist = ray.MaxDistance
+	}
+	end := ray.Origin.Add(ray.direction.Mul(maxDist))
+	return boundsFromPoints(ray.Origin.Add(extent.Min), ray.Origin.Add(extent.Max),
+		end.Add(extent.Min), end.Add(extent.Max))
+}
+
+// reach returns how far along the ray something with the bounds extent
+// around the ray's origin has to move before it is past all of the bounds b.
+func reach(ray *CollisionRay, extent, b Bounds) float32 {
+	var farthest float32
+	for i := 0; i < 8; i++ {
+		corner := b.Min
+		for axis := 0; axis < 3; axis++ {
+			if i&(1<<uint(axis)) != 0 {
+				corner[axis] = b.Max[axis]
+			}
+		}
+		farthest = max32(farthest, corner.Sub(ray.Origin).Len())
+	}
+	return farthest + extent.Max.Sub(extent.Min).Len()
+}
+
+// castVsTriangles sweeps the convex shape against each of the triangles
+// near its path and returns the first one it touches.
+func castVsTriangles(ray *CollisionRay, cast func(t float32) convexShape, extent Bounds, triangles TriangleSet) (int, CastHit) {
+	var closest CastHit
+	found := false
+	triCount := triangles.TriangleCount()
+	if triCount == 0 || !ray.valid() {
+		return NoIntersect, closest
+	}
+
+	bounded := *ray
+	var path Bounds
+	if ray.MaxDistance > 0 {
+		path = sweptBounds(ray, extent, 0)
+	} else {
+		path = infiniteBounds
+	}
+	for i := 0; i < triCount; i++ {
+		v0, v1, v2 := triangles.Triangle(i)
+		if !path.Overlaps(boundsFromPoints(v0, v1, v2)) {
+			continue
+		}
+		result, hit := castConvex(&bounded, cast, 0, triangleShape{v0, v1, v2})
+		if result == Intersect && (!found || hit.Distance < closest.Distance) {
+			closest = hit
+			found = true
+			bounded.MaxDistance = max32(hit.Distance, rayEpsilon)
+		}
+	}
+	if !found {
+		return NoIntersect, closest
+	}
+	return Intersect, closest
+}
+
+// castVsVoxels sweeps the convex shape against each of the solid voxels
+// near its path and returns the first one it touches.
+func castVsVoxels(ray *CollisionRay, cast func(t float32) convexShape, extent Bounds, vg *VoxelGrid) (int, CastHit) {
+	var closest CastHit
+	found := false
+	if !ray.valid() {
+		return NoIntersect, closest
+	}
+
+	gridBounds := ColliderBounds(vg)
+	path := sweptBounds(ray, extent, reach(ray, extent, gridBounds))
+	lo, hi, ok := vg.voxelRange(path.Min, path.Max)
+	if !ok {
+		return NoIntersect, closest
+	}
+	bounded := *ray
+	var voxel AABBox
+	for z := lo[2]; z <= hi[2]; z++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for x := lo[0]; x <= hi[0]; x++ {
+				if !vg.IsSolid(x, y, z) {
+					continue
+				}
+				voxel.Min, voxel.Max = vg.VoxelBounds(x, y, z)
+				result, hit := castConvex(&bounded, cast, 0, &voxel)
+				if result == Intersect && (!found || hit.Distance < closest.Distance) {
+					closest = hit
+					found = true
+					bounded.MaxDistance = max32(hit.Distance, rayEpsilon)
+				}
+			}
+		}
+	}
+	if !found {
+		return NoIntersect, closest
+	}
+	return Intersect, closest
+}
+
+// castAlong returns a function that moves the shape the distance t along
+// the ray's direction, for use with castConvex.
+func castAlong(ray *CollisionRay, shape convexShape) func(t float32) convexShape {
+	dir := ray.direction
+	return func(t float32) convexShape {
+		return translatedShape{shape, dir.Mul(t)}
+	}
+}
+
+// castShape sweeps the convex shape, placed at the ray's origin, along the
+// ray against the collider. The extent is the shape's bounds around the
+// ray's origin.
+func castShape(ray *CollisionRay, shape convexShape, extent Bounds, c Collider) (int, CastHit) {
+	cast := castAlong(ray, shape)
+	var result int
+	var hit CastHit
+	switch s := c.(type) {
+	case *AABBox:
+		result, hit = castConvex(ray, cast, 0, s)
+	case *Sphere:
+		result, hit = castConvex(ray, cast, 0, s)
+	case *Ellipsoid:
+		result, hit = castConvex(ray, cast, 0, s)
+	case *Cylinder:
+		result, hit = castConvex(ray, cast, 0, s)
+	case *Cone:
+		result, hit = castConvex(ray, cast, 0, s)
+	case *Triangle:
+		result, hit = castVsTriangles(ray, cast, extent, singleTriangle{s})
+	case *VoxelGrid:
+		result, hit = castVsVoxels(ray, cast, extent, s)
+	case TriangleSet:
+		result, hit = castVsTriangles(ray, cast, extent, s)
+	default:
+		return NoIntersect, hit
+	}
+	hit.Collider = c
+	return result, hit
+}
+
+// castFirst runs the cast against each of the colliders that pass the
+// filters and returns the closest hit.
+func castFirst(colliders []Collider, filters []TagFilter, cast func(c Collider) (int, CastHit)) (CastHit, bool) {
+	var closest CastHit
+	found := false
+	for _, c := range colliders {
+		if !passesTagFilters(c, filters) {
+			continue
+		}
+		result, hit := cast(c)
+		if result == Intersect && (!found || hit.Distance < closest.Distance) {
+			closest = hit
+			found = true
+		}
+	}
+	return closest, found
+}
+
+// translatedShape is a convex shape moved by an offset.
+type translatedShape struct {
+	shape  convexShape
+	offset mgl.Vec3
+}
+
+func (ts translatedShape) support(d mgl.Vec3) mgl.Vec3 {
+	return ts.shape.support(d).Add(ts.offset)
+}
+
+// triangleShape is a triangle usable as a convexShape.
+type triangleShape [3]mgl.Vec3
+
+func (ts triangleShape) support(d mgl.Vec3) mgl.Vec3 {
+	best := ts[0]
+	bestDot := best.Dot(d)
+	for _, v := range ts[1:] {
+		if dot := v.Dot(d); dot > bestDot {
+			best, bestDot = v, dot
+		}
+	}
+	return best
+}
+
+// singleTriangle lets a Triangle be used as a TriangleSet.
+type singleTriangle struct {
+	tri *Triangle
+}
+
+func (st singleTriangle) TriangleCount() int {
+	return 1
+}
+
+func (st singleTriangle) Triangle(i int) (mgl.Vec3, mgl.Vec3, mgl.Vec3) {
+	return st.tri.vertices()
+}
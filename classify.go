@@ -0,0 +1,55 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+// Classification is the result of the Classify* functions which, unlike the
+// CollideVs* functions, report whether one shape contains another.
+type Classification int
+
+const (
+	// Outside means the shape is completely outside of the other shape.
+	Outside Classification = iota
+
+	// Inside means the shape is completely inside of the other shape.
+	Inside
+
+	// Intersecting means the shape straddles the boundary of the other shape.
+	Intersecting
+)
+
+const (
+	// Back means the shape is completely behind a plane and is the same as Outside.
+	Back = Outside
+
+	// Front means the shape is completely in front of a plane and is the same as Inside.
+	Front = Inside
+
+	// Straddling means the shape crosses a plane and is the same as Intersecting.
+	Straddling = Intersecting
+)
+
+// String returns the name of the classification.
+func (c Classification) String() string {
+	switch c {
+	case Outside:
+		return "Outside"
+	case Inside:
+		return "Inside"
+	case Intersecting:
+		return "Intersecting"
+	}
+	return "Unknown"
+}
+
+// classifyExtentVsPlane classifies a shape centered at a signed distance
+// from a plane that extends radius units along the plane's normal.
+func classifyExtentVsPlane(dist, radius float32) Classification {
+	if dist+radius < 0 {
+		return Back
+	}
+	if dist-radius >= 0 {
+		return Front
+	}
+	return Straddling
+}
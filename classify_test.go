@@ -0,0 +1,131 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestAABBoxClassifyVsAABBox(t *testing.T) {
+	var b1, b2 AABBox
+	b1.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	b1.Max = mgl.Vec3{1.0, 1.0, 1.0}
+	b2.Min = mgl.Vec3{-10.0, -10.0, -10.0}
+	b2.Max = mgl.Vec3{10.0, 10.0, 10.0}
+
+	if c := b1.ClassifyVsAABBox(&b2); c != Inside {
+		t.Errorf("AABBox.ClassifyVsAABBox() returned %v for a box inside another.", c)
+	}
+	if c := b2.ClassifyVsAABBox(&b1); c != Intersecting {
+		t.Errorf("AABBox.ClassifyVsAABBox() returned %v for a box containing another.", c)
+	}
+
+	b1.Offset = mgl.Vec3{10.0, 0.0, 0.0}
+	if c := b1.ClassifyVsAABBox(&b2); c != Intersecting {
+		t.Errorf("AABBox.ClassifyVsAABBox() returned %v for a box straddling another.", c)
+	}
+
+	b1.Offset = mgl.Vec3{20.0, 0.0, 0.0}
+	if c := b1.ClassifyVsAABBox(&b2); c != Outside {
+		t.Errorf("AABBox.ClassifyVsAABBox() returned %v for a box outside another.", c)
+	}
+}
+
+func TestSphereClassifyVsAABBox(t *testing.T) {
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-10.0, -10.0, -10.0}
+	b1.Max = mgl.Vec3{10.0, 10.0, 10.0}
+
+	sphere := Sphere{Center: mgl.Vec3{0.0, 0.0, 0.0}, Radius: 5.0}
+	if c := sphere.ClassifyVsAABBox(&b1); c != Inside {
+		t.Errorf("Sphere.ClassifyVsAABBox() returned %v for a sphere inside a box.", c)
+	}
+	if c := b1.ClassifyVsSphere(&sphere); c != Intersecting {
+		t.Errorf("AABBox.ClassifyVsSphere() returned %v for a box containing a sphere.", c)
+	}
+
+	sphere.Offset = mgl.Vec3{8.0, 0.0, 0.0}
+	if c := sphere.ClassifyVsAABBox(&b1); c != Intersecting {
+		t.Errorf("Sphere.ClassifyVsAABBox() returned %v for a sphere straddling a box.", c)
+	}
+
+	sphere.Offset = mgl.Vec3{16.0, 0.0, 0.0}
+	if c := sphere.ClassifyVsAABBox(&b1); c != Outside {
+		t.Errorf("Sphere.ClassifyVsAABBox() returned %v for a sphere outside a box.", c)
+	}
+
+	// a sphere large enough to contain the box
+	sphere = Sphere{Center: mgl.Vec3{0.0, 0.0, 0.0}, Radius: 18.0}
+	if c := b1.ClassifyVsSphere(&sphere); c != Inside {
+		t.Errorf("AABBox.ClassifyVsSphere() returned %v for a box inside a sphere.", c)
+	}
+	if c := sphere.ClassifyVsSphere(&Sphere{Radius: 20.0}); c != Inside {
+		t.Errorf("Sphere.ClassifyVsSphere() returned %v for a sphere inside another.", c)
+	}
+}
+
+func TestClassifyVsPlane(t *testing.T) {
+	// Plane @ {0, 0, 0}   Normal---> {1, 0, 0}
+	p := NewPlaneFromNormalAndPoint(mgl.Vec3{1.0, 0.0, 0.0}, mgl.Vec3{0, 0, 0})
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	b1.Max = mgl.Vec3{1.0, 1.0, 1.0}
+	sphere := Sphere{Radius: 1.0}
+	obb := NewOBBox()
+	obb.HalfSize = mgl.Vec3{1, 1, 1}
+
+	offsets := []mgl.Vec3{{5, 0, 0}, {-5, 0, 0}, {0.5, 0, 0}}
+	expected := []Classification{Front, Back, Straddling}
+	for i, offset := range offsets {
+		b1.SetOffset(&offset)
+		sphere.SetOffset(&offset)
+		obb.SetOffset(offset)
+		if c := b1.ClassifyVsPlane(p); c != expected[i] {
+			t.Errorf("AABBox.ClassifyVsPlane() returned %v instead of %v at %v", c, expected[i], offset)
+		}
+		if c := sphere.ClassifyVsPlane(p); c != expected[i] {
+			t.Errorf("Sphere.ClassifyVsPlane() returned %v instead of %v at %v", c, expected[i], offset)
+		}
+		if c := obb.ClassifyVsPlane(p); c != expected[i] {
+			t.Errorf("OBBox.ClassifyVsPlane() returned %v instead of %v at %v", c, expected[i], offset)
+		}
+	}
+
+	// a box rotated 45 degrees reaches further along the normal
+	obb.SetOffset(mgl.Vec3{1.2, 0, 0})
+	if c := obb.ClassifyVsPlane(p); c != Front {
+		t.Errorf("OBBox.ClassifyVsPlane() returned %v for an unrotated box in front of a plane.", c)
+	}
+	obb.SetOrientation(mgl.QuatRotate(mgl.DegToRad(45.0), mgl.Vec3{0, 0, 1}))
+	if c := obb.ClassifyVsPlane(p); c != Straddling {
+		t.Errorf("OBBox.ClassifyVsPlane() returned %v for a rotated box straddling a plane.", c)
+	}
+}
+
+func TestClassifyVsFrustum(t *testing.T) {
+	view := mgl.LookAtV(mgl.Vec3{0, 0, 10}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(mgl.DegToRad(90.0), 1.0, 1.0, 100.0)
+	f := NewFrustumFromMatrix(projection.Mul4(view))
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	b1.Max = mgl.Vec3{1.0, 1.0, 1.0}
+	sphere := Sphere{Radius: 1.0}
+
+	offsets := []mgl.Vec3{{0, 0, 0}, {0, 0, 20}, {0, 0, 9.5}, {50, 0, 0}, {0, 0, -90}, {0, 0, -200}}
+	expected := []Classification{Inside, Outside, Intersecting, Outside, Intersecting, Outside}
+	for i, offset := range offsets {
+		b1.SetOffset(&offset)
+		sphere.SetOffset(&offset)
+		if c := b1.ClassifyVsFrustum(f); c != expected[i] {
+			t.Errorf("AABBox.ClassifyVsFrustum() returned %v instead of %v at %v", c, expected[i], offset)
+		}
+		if c := sphere.ClassifyVsFrustum(f); c != expected[i] {
+			t.Errorf("Sphere.ClassifyVsFrustum() returned %v instead of %v at %v", c, expected[i], offset)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// Cone is a capped cone defined by the point of its apex, its height and
+// the radius of its base. Without any rotation it opens up along the Y
+// axis from the apex, so a cone rotated to point along a direction makes
+// a good spotlight or vision volume.
+type Cone struct {
+	// Apex is the tip of the cone, in local space (model-space in 3d graphics)
+	Apex mgl.Vec3
+
+	// Offset is the world-space location of the that can be considered an offset to Apex
+	Offset mgl.Vec3
+
+	// Height is the distance from the apex to the center of the base.
+	Height float32
+
+	// Radius is the radius of the base of the cone.
+	Radius float32
+
+	orientation mgl.Quat
+
+	// Tags provides a way to label a cone in a custom application
+	// (e.g. labelling a collision as "spotlight" or "vision").
+	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// cone is hit, such as the game object that owns it.
+	UserData interface{}
+
+	// Material describes the surface of the cone.
+	Material Material
+}
+
+// NewCone creates a new Cone object.
+func NewCone() *Cone {
+	c := new(Cone)
+	c.orientation = mgl.QuatIdent()
+	return c
+}
+
+// NewConeFromDirection creates a new Cone with its apex at apex that opens
+// along direction out to a distance of height. halfAngle is the angle, in
+// radians, between the cone's axis and its side.
+func NewConeFromDirection(apex, direction mgl.Vec3, height, halfAngle float32) *Cone {
+	c := new(Cone)
+	c.Apex = apex
+	c.Height = height
+	c.Radius = height * float32(math.Tan(float64(halfAngle)))
+	c.orientation = mgl.QuatBetweenVectors(mgl.Vec3{0, 1, 0}, direction)
+	return c
+}
+
+// SetOffset changes the offset of the collision object.
+func (c *Cone) SetOffset(offset *mgl.Vec3) {
+	c.Offset = *offset
+}
+
+// SetOffset3f changes the offset of the collision object.
+func (c *Cone) SetOffset3f(x, y, z float32) {
+	c.Offset[0] = x
+	c.Offset[1] = y
+	c.Offset[2] = z
+}
+
+// SetOrientation sets the rotation of the cone.
+func (c *Cone) SetOrientation(q mgl.Quat) {
+	c.orientation = q
+}
+
+// GetOrientation gets the rotation of the cone.
+func (c *Cone) GetOrientation() mgl.Quat {
+	return orientationOrIdent(c.orientation)
+}
+
+// GetAxis returns the world-space direction the cone opens along.
+func (c *Cone) GetAxis() mgl.Vec3 {
+	return c.GetOrientation().Rotate(mgl.Vec3{0, 1, 0})
+}
+
+// Validate checks the Cone for non-finite values, a negative size and
+// an orientation that isn't a unit quaternion.
+func (c *Cone) Validate() error {
+	if err := validateFinite("Cone", "Apex", c.Apex[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("Cone", "Offset", c.Offset[:]); err != nil {
+		return err
+	}
+	if !isFinite32(c.Radius) || !isFinite32(c.Height) {
+		return &ShapeError{Shape: "Cone", Field: "Radius", Err: ErrNonFinite}
+	}
+	if c.Radius < 0 {
+		return &ShapeError{Shape: "Cone", Field: "Radius", Err: ErrNegativeRadius}
+	}
+	if c.Height < 0 {
+		return &ShapeError{Shape: "Cone", Field: "Height", Err: ErrNegativeSize}
+	}
+	return validateOrientation("Cone", c.GetOrientation())
+}
+
+// support returns the point on the cone furthest along d.
+func (c *Cone) support(d mgl.Vec3) mgl.Vec3 {
+	q := c.GetOrientation()
+	local := q.Conjugate().Rotate(d)
+
+	// the furthest point is either the apex or on the rim of the base
+	rim := mgl.Vec3{0, c.Height, 0}
+	radial := float32(math.Sqrt(float64(local[0]*local[0] + local[2]*local[2])))
+	if radial > 0 {
+		rim[0] = local[0] * c.Radius / radial
+		rim[2] = local[2] * c.Radius / radial
+	}
+
+	apex := c.Apex.Add(c.Offset)
+	if rim.Dot(local) <= 0 {
+		return apex
+	}
+	return apex.Add(q.Rotate(rim))
+}
+
+// CollideVsSphere tests a collision between a cone and a sphere.
+func (c *Cone) CollideVsSphere(s *Sphere) int {
+	if gjkIntersect(c, s) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsSphereFast is a quicker but conservative test of a cone against a
+// sphere meant for culling spheres against spotlights and vision cones. It
+// never misses a sphere touching the cone, but may report spheres that are
+// just beyond the rim of the cone's base as intersecting.
+func (c *Cone) CollideVsSphereFast(s *Sphere) int {
+	// implementation based on Bart Wronski's "Cull that cone!"
+	v := s.Center.Add(s.Offset).Sub(c.Apex.Add(c.Offset))
+	vlenSq := v.Dot(v)
+	v1len := v.Dot(c.GetAxis())
+
+	// outside of the slab between the apex and the base
+	if v1len > c.Height+s.Radius || v1len < -s.Radius {
+		return NoIntersect
+	}
+
+	// the distance from the sphere's center to the side of the cone
+	slant := float32(math.Sqrt(float64(c.Height*c.Height + c.Radius*c.Radius)))
+	if slant == 0 {
+		return NoIntersect
+	}
+	cosAngle := c.Height / slant
+	sinAngle := c.Radius / slant
+	perpendicular := float32(math.Sqrt(float64(max32(vlenSq-v1len*v1len, 0))))
+	if cosAngle*perpendicular-v1len*sinAngle > s.Radius {
+		return NoIntersect
+	}
+	return Intersect
+}
+
+// CollideVsAABBox tests a collision between a cone and an AABBox.
+func (c *Cone) CollideVsAABBox(b *AABBox) int {
+	if gjkIntersect(c, b) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsPlane tests a collision between a cone and a plane. Like the
+// other shapes, the cone only fails to intersect if it lies completely
+// behind the plane.
+func (c *Cone) CollideVsPlane(p *Plane) int {
+	return convexVsPlane(c, p)
+}
+
+// CollideVsRay tests a collision between a cone and a ray and returns the
+// distance along the ray to the surface of the cone. If the ray starts
+// inside the cone the distance is zero.
+func (c *Cone) CollideVsRay(ray *CollisionRay) (int, float32) {
+	result, dist, _ := c.CollideVsRayWithNormal(ray)
+	return result, dist
+}
+
+// CollideVsRayWithNormal tests a collision between a cone and a ray and
+// returns the distance along the ray and the world-space surface normal at
+// the hit. If the ray starts inside the cone the distance is zero and the
+// normal faces back along the ray.
+func (c *Cone) CollideVsRayWithNormal(ray *CollisionRay) (int, float32, mgl.Vec3) {
+	if !ray.valid() || c.Height <= 0 {
+		return NoIntersect, 0.0, mgl.Vec3{}
+	}
+
+	// do all of the work in the cone's local space with the apex at the origin
+	q := c.GetOrientation()
+	qInv := q.Conjugate()
+	o := qInv.Rotate(ray.Origin.Sub(c.Apex.Add(c.Offset)))
+	d := qInv.Rotate(ray.direction)
+	k := c.Radius / c.Height
+	kSq := k * k
+
+	radialSq := o[0]*o[0] + o[2]*o[2]
+	if o[1] >= 0 && o[1] <= c.Height && radialSq <= kSq*o[1]*o[1] {
+		return Intersect, 0.0, ray.direction.Mul(-1.0)
+	}
+
+	found := false
+	var best float32
+	var normal mgl.Vec3
+
+	// test the side of the cone: x*x + z*z = k*k*y*y with 0 <= y <= height
+	a := d[0]*d[0] + d[2]*d[2] - kSq*d[1]*d[1]
+	b := o[0]*d[0] + o[2]*d[2] - kSq*o[1]*d[1]
+	cc := radialSq - kSq*o[1]*o[1]
+	var roots [2]float32
+	rootCount := 0
+	if a > rayEpsilon || a < -rayEpsilon {
+		disc := b*b - a*cc
+		if disc >= 0 {
+			sqrtDisc := float32(math.Sqrt(float64(disc)))
+			roots[0] = (-b - sqrtDisc) / a
+			roots[1] = (-b + sqrtDisc) / a
+			if roots[0] > roots[1] {
+				roots[0], roots[1] = roots[1], roots[0]
+			}
+			rootCount = 2
+		}
+	} else if b > rayEpsilon || b < -rayEpsilon {
+		roots[0] = -cc / (2.0 * b)
+		rootCount = 1
+	}
+	for i := 0; i < rootCount; i++ {
+		t := roots[i]
+		y := o[1] + t*d[1]
+		if t < 0 || y < 0 || y > c.Height {
+			continue
+		}
+		found = true
+		best = t
+		p := o.Add(d.Mul(t))
+		normal = mgl.Vec3{p[0], -kSq * p[1], p[2]}
+		if l := normal.Len(); l > 0 {
+			normal = normal.Mul(1.0 / l)
+		} else {
+			// hit the apex exactly
+			normal = mgl.Vec3{0, -1, 0}
+		}
+		break
+	}
+
+	// test the base of the cone
+	if d[1] > rayEpsilon || d[1] < -rayEpsilon {
+		t := (c.Height - o[1]) / d[1]
+		if t >= 0 && (!found || t < best) {
+			x := o[0] + t*d[0]
+			z := o[2] + t*d[2]
+			if x*x+z*z <= c.Radius*c.Radius {
+				found = true
+				best = t
+				normal = mgl.Vec3{0, 1, 0}
+			}
+		}
+	}
+
+	if !found || !ray.inRange(best) {
+		return NoIntersect, 0.0, mgl.Vec3{}
+	}
+	return Intersect, best, q.Rotate(normal)
+}
@@ -0,0 +1,127 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestSpotCone makes a cone like a spotlight at the origin shining down -Z
+// out to 10 units with a 45 degree half angle.
+func newTestSpotCone() *Cone {
+	return NewConeFromDirection(mgl.Vec3{}, mgl.Vec3{0, 0, -1}, 10.0, mgl.DegToRad(45.0))
+}
+
+func TestConeCollisionVsSphere(t *testing.T) {
+	c := newTestSpotCone()
+	if c.GetAxis().Sub(mgl.Vec3{0, 0, -1}).Len() > 1e-4 {
+		t.Errorf("NewConeFromDirection() made a cone with the wrong axis: %v", c.GetAxis())
+	}
+	if !mgl.FloatEqualThreshold(c.Radius, 10.0, 1e-4) {
+		t.Errorf("NewConeFromDirection() made a cone with the wrong radius: %f", c.Radius)
+	}
+
+	tests := []struct {
+		center   mgl.Vec3
+		radius   float32
+		expected int
+	}{
+		{mgl.Vec3{0, 0, -5}, 0.5, Intersect},      // on the axis
+		{mgl.Vec3{4, 0, -5}, 0.5, Intersect},      // inside near the side
+		{mgl.Vec3{5.5, 0, -5}, 1.0, Intersect},    // straddling the side
+		{mgl.Vec3{8, 0, -5}, 1.0, NoIntersect},    // beside the cone
+		{mgl.Vec3{0, 0, 2}, 1.0, NoIntersect},     // behind the apex
+		{mgl.Vec3{0, 0, -11.5}, 1.0, NoIntersect}, // beyond the base
+		{mgl.Vec3{0, 0, -10.5}, 1.0, Intersect},   // touching the base
+	}
+
+	for i, test := range tests {
+		s := Sphere{Center: test.center, Radius: test.radius}
+		if result := c.CollideVsSphere(&s); result != test.expected {
+			t.Errorf("Cone.CollideVsSphere() returned %d instead of %d for sphere %d.", result, test.expected, i)
+		}
+
+		// the fast test is conservative so it only has to agree on hits
+		if test.expected == Intersect && c.CollideVsSphereFast(&s) != Intersect {
+			t.Errorf("Cone.CollideVsSphereFast() missed sphere %d that intersects.", i)
+		}
+	}
+
+	// the fast test should still reject spheres clearly outside the cone
+	s := Sphere{Center: mgl.Vec3{8, 0, -5}, Radius: 1.0}
+	if c.CollideVsSphereFast(&s) != NoIntersect {
+		t.Error("Cone.CollideVsSphereFast() indicated a sphere beside the cone collided.")
+	}
+	s = Sphere{Center: mgl.Vec3{0, 0, 2}, Radius: 1.0}
+	if c.CollideVsSphereFast(&s) != NoIntersect {
+		t.Error("Cone.CollideVsSphereFast() indicated a sphere behind the apex collided.")
+	}
+}
+
+func TestConeCollisionVsShapes(t *testing.T) {
+	c := newTestSpotCone()
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-0.5, -0.5, -0.5}
+	b1.Max = mgl.Vec3{0.5, 0.5, 0.5}
+	b1.Offset = mgl.Vec3{0.0, 0.0, -5.0}
+	if c.CollideVsAABBox(&b1) != Intersect {
+		t.Error("Cone.CollideVsAABBox() indicated a box didn't collide that should have.")
+	}
+	b1.Offset = mgl.Vec3{0.0, 0.0, 1.0}
+	if c.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("Cone.CollideVsAABBox() indicated a box behind the apex collided.")
+	}
+
+	p := NewPlaneFromNormalAndPoint(mgl.Vec3{0, 0, -1}, mgl.Vec3{0, 0, -9})
+	if c.CollideVsPlane(p) != Intersect {
+		t.Error("Cone.CollideVsPlane() indicated a plane didn't collide that should have.")
+	}
+	p = NewPlaneFromNormalAndPoint(mgl.Vec3{0, 0, -1}, mgl.Vec3{0, 0, -11})
+	if c.CollideVsPlane(p) != NoIntersect {
+		t.Error("Cone.CollideVsPlane() indicated a plane collided that should not have.")
+	}
+}
+
+func TestConeCollisionVsRay(t *testing.T) {
+	c := NewCone()
+	c.Height = 2.0
+	c.Radius = 1.0
+	c.SetOffset3f(0.0, 0.0, -10.0)
+
+	// hit the base from below
+	ray, _ := NewCollisionRay(mgl.Vec3{0.0, 5.0, -10.0}, mgl.Vec3{0, -1, 0})
+	result, dist, normal := c.CollideVsRayWithNormal(ray)
+	if result != Intersect || !mgl.FloatEqualThreshold(dist, 3.0, 1e-4) {
+		t.Errorf("Cone.CollideVsRayWithNormal() didn't hit the base correctly: %d %f", result, dist)
+	}
+	if !normal.ApproxEqualThreshold(mgl.Vec3{0, 1, 0}, 1e-4) {
+		t.Errorf("Cone.CollideVsRayWithNormal() returned the wrong normal for the base: %v", normal)
+	}
+
+	// hit the side halfway up where the radius is 0.5
+	ray, _ = NewCollisionRay(mgl.Vec3{-5.0, 1.0, -10.0}, mgl.Vec3{1, 0, 0})
+	result, dist, normal = c.CollideVsRayWithNormal(ray)
+	if result != Intersect || !mgl.FloatEqualThreshold(dist, 4.5, 1e-4) {
+		t.Errorf("Cone.CollideVsRayWithNormal() didn't hit the side correctly: %d %f", result, dist)
+	}
+	if normal[0] >= 0 || normal[1] >= 0 || !mgl.FloatEqualThreshold(normal.Len(), 1.0, 1e-4) {
+		t.Errorf("Cone.CollideVsRayWithNormal() returned the wrong normal for the side: %v", normal)
+	}
+
+	// pass below the apex
+	ray, _ = NewCollisionRay(mgl.Vec3{-5.0, -0.5, -10.0}, mgl.Vec3{1, 0, 0})
+	if result, _ := c.CollideVsRay(ray); result != NoIntersect {
+		t.Error("Cone.CollideVsRay() hit when the ray should have passed below the apex.")
+	}
+
+	// a segment that stops short of the cone
+	ray.Origin = mgl.Vec3{-5.0, 1.0, -10.0}
+	ray.MaxDistance = 4.0
+	if result, _ := c.CollideVsRay(ray); result != NoIntersect {
+		t.Error("Cone.CollideVsRay() hit beyond the ray's MaxDistance.")
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+// Handle is a stable identifier for a collider that has been added to a
+// ContactTracker. The zero Handle never refers to a collider.
+type Handle uint32
+
+// ContactEventKind describes how a pair of colliders changed between steps.
+type ContactEventKind int
+
+const (
+	// ContactBegin means the pair started colliding this step.
+	ContactBegin ContactEventKind = iota
+
+	// ContactStay means the pair was colliding last step and still is.
+	ContactStay
+
+	// ContactEnd means the pair was colliding last step but isn't any more,
+	// or one of the pair was removed.
+	ContactEnd
+)
+
+// String returns the name of the event kind.
+func (k ContactEventKind) String() string {
+	switch k {
+	case ContactBegin:
+		return "Begin"
+	case ContactStay:
+		return "Stay"
+	case ContactEnd:
+		return "End"
+	}
+	return "Unknown"
+}
+
+// ContactEvent describes a change in the contact between two colliders.
+type ContactEvent struct {
+	// Kind is whether the contact began, stayed or ended.
+	Kind ContactEventKind
+
+	// A is the handle of the collider that was added first.
+	A Handle
+
+	// B is the handle of the collider that was added second.
+	B Handle
+
+	// TagsA are the Tags of collider A.
+	TagsA []string
+
+	// TagsB are the Tags of collider B.
+	TagsB []string
+
+	// TagSetA is the TagSet of collider A.
+	TagSetA TagSet
+
+	// TagSetB is the TagSet of collider B.
+	TagSetB TagSet
+
+	// UserDataA is the UserData of collider A.
+	UserDataA interface{}
+
+	// UserDataB is the UserData of collider B.
+	UserDataB interface{}
+
+	// MaterialA is the Material of collider A.
+	MaterialA Material
+
+	// MaterialB is the Material of collider B.
+	MaterialB Material
+}
+
+// ContactTracker tests every pair of its colliders each step and reports
+// when pairs begin touching, stay touching and stop touching. The events
+// are passed to the callbacks, if set, and kept in Events until the next
+// step.
+type ContactTracker struct {
+	// OnBegin is called for every pair that started colliding in a step.
+	OnBegin func(e ContactEvent)
+
+	// OnStay is called for every pair that is still colliding in a step.
+	OnStay func(e ContactEvent)
+
+	// OnEnd is called for every pair that stopped colliding in a step.
+	OnEnd func(e ContactEvent)
+
+	// Events holds all of the events from the last step in the order that
+	// they happened.
+	Events []ContactEvent
+
+	colliders    map[Handle]Collider
+	order        []Handle
+	nextHandle   Handle
+	active       map[HandlePair]int
+	activeEvents []ContactEvent
+
+	// the pairs from the step before last, kept to be reused
+	spare       map[HandlePair]int
+	spareEvents []ContactEvent
+}
+
+// NewContactTracker creates a new ContactTracker object without any colliders.
+func NewContactTracker() *ContactTracker {
+	t := new(ContactTracker)
+	t.colliders = make(map[Handle]Collider)
+	t.active = make(map[HandlePair]int)
+	t.spare = make(map[HandlePair]int)
+	return t
+}
+
+// Add starts tracking the collider and returns its handle. The collider
+// can still be moved with SetOffset between steps.
+func (t *ContactTracker) Add(c Collider) Handle {
+	t.nextHandle++
+	h := t.nextHandle
+	t.colliders[h] = c
+	t.order = append(t.order, h)
+	return h
+}
+
+// Remove stops tracking the collider. Any pairs it was colliding with get a
+// ContactEnd event on the next step.
+func (t *ContactTracker) Remove(h Handle) {
+	if _, okay := t.colliders[h]; !okay {
+		return
+	}
+	delete(t.colliders, h)
+	for i, oh := range t.order {
+		if oh == h {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Collider returns the collider for the handle. The second return value is
+// false if the handle isn't being tracked.
+func (t *ContactTracker) Collider(h Handle) (Collider, bool) {
+	c, okay := t.colliders[h]
+	return c, okay
+}
+
+// Step tests every pair of colliders with the same tests as World.Step and
+// returns the events for this step. The returned slice is reused by the next step.
+func (t *ContactTracker) Step() []ContactEvent {
+	t.Events = t.Events[:0]
+	current := t.spare
+	for pair := range current {
+		delete(current, pair)
+	}
+	currentEvents := t.spareEvents[:0]
+
+	for i, ha := range t.order {
+		ca := t.colliders[ha]
+		for _, hb := range t.order[i+1:] {
+			cb := t.colliders[hb]
+			if collidePair(ca, cb) != Intersect {
+				continue
+			}
+
+			pair := HandlePair{ha, hb}
+			e := ContactEvent{Kind: ContactBegin, A: ha, B: hb}
+			pa, pb := colliderProperties(ca), colliderProperties(cb)
+			e.TagsA, e.TagSetA, e.UserDataA, e.MaterialA = pa.tags, pa.tagSet, pa.userData, pa.material
+			e.TagsB, e.TagSetB, e.UserDataB, e.MaterialB = pb.tags, pb.tagSet, pb.userData, pb.material
+			if _, okay := t.active[pair]; okay {
+				e.Kind = ContactStay
+			}
+			current[pair] = len(currentEvents)
+			currentEvents = append(currentEvents, e)
+			t.emit(e)
+		}
+	}
+
+	// anything that was colliding last step but isn't now has ended
+	for _, e := range t.activeEvents {
+		if _, okay := current[HandlePair{e.A, e.B}]; okay {
+			continue
+		}
+		e.Kind = ContactEnd
+		t.emit(e)
+	}
+
+	t.spare, t.spareEvents = t.active, t.activeEvents
+	t.active, t.activeEvents = current, currentEvents
+	return t.Events
+}
+
+// emit records the event and calls the matching callback.
+func (t *ContactTracker) emit(e ContactEvent) {
+	t.Events = append(t.Events, e)
+
+	var callback func(ContactEvent)
+	switch e.Kind {
+	case ContactBegin:
+		callback = t.OnBegin
+	case ContactStay:
+		callback = t.OnStay
+	case ContactEnd:
+		callback = t.OnEnd
+	}
+	if callback != nil {
+		callback(e)
+	}
+}
+
+// colliderProps holds the properties every shape in this package has.
+type colliderProps struct {
+	tags     []string
+	tagSet   TagSet
+	userData interface{}
+	material Material
+}
+
+// colliderProperties returns the Tags, TagSet, UserData and Material of any
+// of the shapes in this package.
+func colliderProperties(c Collider) colliderProps {
+	switch s := c.(type) {
+	case *AABBox:
+		return colliderProps{s.Tags, s.TagSet, s.UserData, s.Material}
+	case *Sphere:
+		return colliderProps{s.Tags, s.TagSet, s.UserData, s.Material}
+	case *Triangle:
+		return colliderProps{s.Tags, s.TagSet, s.UserData, s.Material}
+	case *Mesh:
+		return colliderProps{s.Tags, s.TagSet, s.UserData, s.Material}
+	case *Ellipsoid:
+		return colliderProps{s.Tags, s.TagSet, s.UserData, s.Material}
+	case *Cylinder:
+		return colliderProps{s.Tags, s.TagSet, s.UserData, s.Material}
+	case *Cone:
+		return colliderProps{s.Tags, s.TagSet, s.UserData, s.Material}
+	case *Heightfield:
+		return colliderProps{s.Tags, s.TagSet, s.UserData, s.Material}
+	case *VoxelGrid:
+		return colliderProps{s.Tags, s.TagSet, s.UserData, s.Material}
+	}
+	return colliderProps{}
+}
+
+// ColliderTags returns the Tags of any of the shapes in this package. Colliders
+// from other packages have no tags.
+func ColliderTags(c Collider) []string {
+	return colliderProperties(c).tags
+}
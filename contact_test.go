@@ -0,0 +1,145 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestContactTrackerEvents(t *testing.T) {
+	tracker := NewContactTracker()
+
+	zone := NewAABBox()
+	zone.Min = mgl.Vec3{-1, -1, -1}
+	zone.Max = mgl.Vec3{1, 1, 1}
+	zone.Tags = []string{"trigger"}
+	zoneHandle := tracker.Add(zone)
+
+	player := NewSphere()
+	player.Radius = 0.5
+	player.Tags = []string{"player"}
+	player.UserData = 42
+	player.Material.SurfaceType = 7
+	player.SetOffset3f(5.0, 0.0, 0.0)
+	playerHandle := tracker.Add(player)
+
+	var began, stayed, ended int
+	tracker.OnBegin = func(e ContactEvent) { began++ }
+	tracker.OnStay = func(e ContactEvent) { stayed++ }
+	tracker.OnEnd = func(e ContactEvent) { ended++ }
+
+	if events := tracker.Step(); len(events) != 0 {
+		t.Errorf("ContactTracker.Step() returned events for colliders that aren't touching: %v", events)
+	}
+
+	// walk into the trigger zone
+	player.SetOffset3f(1.2, 0.0, 0.0)
+	events := tracker.Step()
+	if len(events) != 1 || events[0].Kind != ContactBegin {
+		t.Fatalf("ContactTracker.Step() didn't return a begin event: %v", events)
+	}
+	e := events[0]
+	if e.A != zoneHandle || e.B != playerHandle {
+		t.Errorf("ContactTracker.Step() returned the wrong handles: %d %d", e.A, e.B)
+	}
+	if len(e.TagsA) != 1 || e.TagsA[0] != "trigger" || len(e.TagsB) != 1 || e.TagsB[0] != "player" {
+		t.Errorf("ContactTracker.Step() returned the wrong tags: %v %v", e.TagsA, e.TagsB)
+	}
+	if e.UserDataA != nil || e.UserDataB != 42 || e.MaterialB.SurfaceType != 7 {
+		t.Errorf("ContactTracker.Step() returned the wrong user data or material: %v %v %v", e.UserDataA, e.UserDataB, e.MaterialB)
+	}
+
+	// stay in the zone
+	events = tracker.Step()
+	if len(events) != 1 || events[0].Kind != ContactStay {
+		t.Errorf("ContactTracker.Step() didn't return a stay event: %v", events)
+	}
+
+	// walk back out
+	player.SetOffset3f(5.0, 0.0, 0.0)
+	events = tracker.Step()
+	if len(events) != 1 || events[0].Kind != ContactEnd || events[0].TagsB[0] != "player" {
+		t.Errorf("ContactTracker.Step() didn't return an end event: %v", events)
+	}
+
+	if began != 1 || stayed != 1 || ended != 1 {
+		t.Errorf("ContactTracker callbacks were called the wrong number of times: %d %d %d", began, stayed, ended)
+	}
+}
+
+func TestContactTrackerConvexTrigger(t *testing.T) {
+	tracker := NewContactTracker()
+
+	// a spotlight shaped trigger and an ellipsoid walking through it
+	light := newTestSpotCone()
+	lightHandle := tracker.Add(light)
+	guard := NewEllipsoid()
+	guard.Radii = mgl.Vec3{0.5, 1.0, 0.5}
+	guard.SetOffset3f(0.0, 8.0, -5.0)
+	guardHandle := tracker.Add(guard)
+
+	if events := tracker.Step(); len(events) != 0 {
+		t.Errorf("ContactTracker.Step() returned events for an ellipsoid outside the cone: %v", events)
+	}
+
+	guard.SetOffset3f(0.0, 0.0, -5.0)
+	events := tracker.Step()
+	if len(events) != 1 || events[0].Kind != ContactBegin || events[0].A != lightHandle || events[0].B != guardHandle {
+		t.Fatalf("ContactTracker.Step() didn't begin a contact for an ellipsoid in the cone: %v", events)
+	}
+
+	guard.SetOffset3f(0.0, 8.0, -5.0)
+	events = tracker.Step()
+	if len(events) != 1 || events[0].Kind != ContactEnd {
+		t.Errorf("ContactTracker.Step() didn't end the contact when the ellipsoid left the cone: %v", events)
+	}
+}
+
+func TestContactTrackerRemove(t *testing.T) {
+	tracker := NewContactTracker()
+
+	s1 := NewSphere()
+	s1.Radius = 1.0
+	s2 := NewSphere()
+	s2.Radius = 1.0
+	s2.SetOffset3f(1.0, 0.0, 0.0)
+	h1 := tracker.Add(s1)
+	h2 := tracker.Add(s2)
+
+	tracker.Step()
+	tracker.Remove(h2)
+	if _, okay := tracker.Collider(h2); okay {
+		t.Error("ContactTracker.Collider() returned a collider that was removed.")
+	}
+
+	events := tracker.Step()
+	if len(events) != 1 || events[0].Kind != ContactEnd || events[0].A != h1 || events[0].B != h2 {
+		t.Errorf("ContactTracker.Step() didn't end the contact for a removed collider: %v", events)
+	}
+	if events = tracker.Step(); len(events) != 0 {
+		t.Errorf("ContactTracker.Step() returned events after the contact ended: %v", events)
+	}
+}
+
+func TestContactTrackerStepAllocs(t *testing.T) {
+	tracker := NewContactTracker()
+	for i := 0; i < 10; i++ {
+		s := NewSphere()
+		s.Radius = 1.0
+		s.SetOffset3f(float32(i)*1.5, 0.0, 0.0)
+		tracker.Add(s)
+	}
+
+	// the first steps grow the buffers that later steps reuse
+	tracker.Step()
+	tracker.Step()
+	allocs := testing.AllocsPerRun(100, func() {
+		tracker.Step()
+	})
+	if allocs != 0 {
+		t.Errorf("ContactTracker.Step() allocated %v times once its buffers had grown", allocs)
+	}
+}
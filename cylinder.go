@@ -0,0 +1,211 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// Cylinder is a capped cylinder defined by a center point, a radius and a
+// half height. Without any rotation its axis runs along the Y axis.
+type Cylinder struct {
+	// Center is the center point of the cylinder, in local space (model-space in 3d graphics)
+	Center mgl.Vec3
+
+	// Offset is the world-space location of the that can be considered an offset to Center
+	Offset mgl.Vec3
+
+	// Radius determines the size of the cylinder's caps.
+	Radius float32
+
+	// HalfHeight is half of the distance between the cylinder's caps.
+	HalfHeight float32
+
+	orientation mgl.Quat
+
+	// Tags provides a way to label a cylinder in a custom application
+	// (e.g. labelling a collision as "pillar" or "turret").
+	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// cylinder is hit, such as the game object that owns it.
+	UserData interface{}
+
+	// Material describes the surface of the cylinder.
+	Material Material
+}
+
+// NewCylinder creates a new Cylinder object.
+func NewCylinder() *Cylinder {
+	c := new(Cylinder)
+	c.orientation = mgl.QuatIdent()
+	return c
+}
+
+// SetOffset changes the offset of the collision object.
+func (c *Cylinder) SetOffset(offset *mgl.Vec3) {
+	c.Offset = *offset
+}
+
+// SetOffset3f changes the offset of the collision object.
+func (c *Cylinder) SetOffset3f(x, y, z float32) {
+	c.Offset[0] = x
+	c.Offset[1] = y
+	c.Offset[2] = z
+}
+
+// SetOrientation sets the rotation of the cylinder.
+func (c *Cylinder) SetOrientation(q mgl.Quat) {
+	c.orientation = q
+}
+
+// GetOrientation gets the rotation of the cylinder.
+func (c *Cylinder) GetOrientation() mgl.Quat {
+	return orientationOrIdent(c.orientation)
+}
+
+// Validate checks the Cylinder for non-finite values, a negative size and
+// an orientation that isn't a unit quaternion.
+func (c *Cylinder) Validate() error {
+	if err := validateFinite("Cylinder", "Center", c.Center[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("Cylinder", "Offset", c.Offset[:]); err != nil {
+		return err
+	}
+	if !isFinite32(c.Radius) || !isFinite32(c.HalfHeight) {
+		return &ShapeError{Shape: "Cylinder", Field: "Radius", Err: ErrNonFinite}
+	}
+	if c.Radius < 0 {
+		return &ShapeError{Shape: "Cylinder", Field: "Radius", Err: ErrNegativeRadius}
+	}
+	if c.HalfHeight < 0 {
+		return &ShapeError{Shape: "Cylinder", Field: "HalfHeight", Err: ErrNegativeSize}
+	}
+	return validateOrientation("Cylinder", c.GetOrientation())
+}
+
+// support returns the point on the cylinder furthest along d.
+func (c *Cylinder) support(d mgl.Vec3) mgl.Vec3 {
+	q := c.GetOrientation()
+	local := q.Conjugate().Rotate(d)
+
+	var p mgl.Vec3
+	radial := float32(math.Sqrt(float64(local[0]*local[0] + local[2]*local[2])))
+	if radial > 0 {
+		p[0] = local[0] * c.Radius / radial
+		p[2] = local[2] * c.Radius / radial
+	}
+	if local[1] >= 0 {
+		p[1] = c.HalfHeight
+	} else {
+		p[1] = -c.HalfHeight
+	}
+	return c.Center.Add(c.Offset).Add(q.Rotate(p))
+}
+
+// CollideVsSphere tests a collision between a cylinder and a sphere.
+func (c *Cylinder) CollideVsSphere(s *Sphere) int {
+	if gjkIntersect(c, s) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsAABBox tests a collision between a cylinder and an AABBox.
+func (c *Cylinder) CollideVsAABBox(b *AABBox) int {
+	if gjkIntersect(c, b) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsPlane tests a collision between a cylinder and a plane. Like the
+// other shapes, the cylinder only fails to intersect if it lies completely
+// behind the plane.
+func (c *Cylinder) CollideVsPlane(p *Plane) int {
+	return convexVsPlane(c, p)
+}
+
+// CollideVsRay tests a collision between a cylinder and a ray and returns
+// the distance along the ray to the surface of the cylinder. If the ray
+// starts inside the cylinder the distance is zero.
+func (c *Cylinder) CollideVsRay(ray *CollisionRay) (int, float32) {
+	result, dist, _ := c.CollideVsRayWithNormal(ray)
+	return result, dist
+}
+
+// CollideVsRayWithNormal tests a collision between a cylinder and a ray and
+// returns the distance along the ray and the world-space surface normal at
+// the hit. If the ray starts inside the cylinder the distance is zero and
+// the normal faces back along the ray.
+func (c *Cylinder) CollideVsRayWithNormal(ray *CollisionRay) (int, float32, mgl.Vec3) {
+	if !ray.valid() {
+		return NoIntersect, 0.0, mgl.Vec3{}
+	}
+
+	// do all of the work in the cylinder's local space
+	q := c.GetOrientation()
+	qInv := q.Conjugate()
+	o := qInv.Rotate(ray.Origin.Sub(c.Center.Add(c.Offset)))
+	d := qInv.Rotate(ray.direction)
+	rsq := c.Radius * c.Radius
+
+	radialSq := o[0]*o[0] + o[2]*o[2]
+	if radialSq <= rsq && fabs32(o[1]) <= c.HalfHeight {
+		return Intersect, 0.0, ray.direction.Mul(-1.0)
+	}
+
+	found := false
+	var best float32
+	var normal mgl.Vec3
+
+	// test the curved side of the cylinder
+	a := d[0]*d[0] + d[2]*d[2]
+	if a > rayEpsilon {
+		b := o[0]*d[0] + o[2]*d[2]
+		cc := radialSq - rsq
+		disc := b*b - a*cc
+		if disc >= 0 {
+			t := (-b - float32(math.Sqrt(float64(disc)))) / a
+			y := o[1] + t*d[1]
+			if t >= 0 && fabs32(y) <= c.HalfHeight {
+				found = true
+				best = t
+				normal = mgl.Vec3{o[0] + t*d[0], 0, o[2] + t*d[2]}.Mul(1.0 / c.Radius)
+			}
+		}
+	}
+
+	// test the caps of the cylinder
+	if d[1] > rayEpsilon || d[1] < -rayEpsilon {
+		for _, capY := range [2]float32{c.HalfHeight, -c.HalfHeight} {
+			t := (capY - o[1]) / d[1]
+			if t < 0 || (found && t >= best) {
+				continue
+			}
+			x := o[0] + t*d[0]
+			z := o[2] + t*d[2]
+			if x*x+z*z <= rsq {
+				found = true
+				best = t
+				normal = mgl.Vec3{0, 1, 0}
+				if capY < 0 {
+					normal[1] = -1
+				}
+			}
+		}
+	}
+
+	if !found || !ray.inRange(best) {
+		return NoIntersect, 0.0, mgl.Vec3{}
+	}
+	return Intersect, best, q.Rotate(normal)
+}
@@ -0,0 +1,114 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestCylinderCollisionVsShapes(t *testing.T) {
+	c := NewCylinder()
+	c.Radius = 1.0
+	c.HalfHeight = 2.0
+
+	sphere := Sphere{Center: mgl.Vec3{0.0, 2.4, 0.0}, Radius: 0.5}
+	if c.CollideVsSphere(&sphere) != Intersect {
+		t.Error("Cylinder.CollideVsSphere() indicated a sphere didn't collide that should have.")
+	}
+
+	// just past the rim of the top cap
+	sphere = Sphere{Center: mgl.Vec3{1.4, 2.4, 0.0}, Radius: 0.5}
+	if c.CollideVsSphere(&sphere) != NoIntersect {
+		t.Error("Cylinder.CollideVsSphere() indicated a sphere collided past the rim that should not have.")
+	}
+
+	// lay the cylinder down along the x axis
+	c.SetOrientation(mgl.QuatRotate(mgl.DegToRad(90.0), mgl.Vec3{0, 0, 1}))
+	sphere = Sphere{Center: mgl.Vec3{2.4, 0.0, 0.0}, Radius: 0.5}
+	if c.CollideVsSphere(&sphere) != Intersect {
+		t.Error("Cylinder.CollideVsSphere() indicated a sphere didn't collide with a rotated cylinder.")
+	}
+	if Collide(c, &sphere) != Intersect {
+		t.Error("Collide() indicated a sphere didn't collide with a rotated cylinder.")
+	}
+	c.SetOrientation(mgl.QuatIdent())
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-0.5, -0.5, -0.5}
+	b1.Max = mgl.Vec3{0.5, 0.5, 0.5}
+	b1.Offset = mgl.Vec3{1.4, 0.0, 0.0}
+	if c.CollideVsAABBox(&b1) != Intersect {
+		t.Error("Cylinder.CollideVsAABBox() indicated a box didn't collide that should have.")
+	}
+
+	// a box that would hit the corner of a square prism but misses the round side
+	b1.Offset = mgl.Vec3{1.3, 0.0, 1.3}
+	if c.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("Cylinder.CollideVsAABBox() indicated a box collided off the curved side that should not have.")
+	}
+
+	p := NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 1.9, 0})
+	if c.CollideVsPlane(p) != Intersect {
+		t.Error("Cylinder.CollideVsPlane() indicated a plane didn't collide that should have.")
+	}
+	p = NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 2.1, 0})
+	if c.CollideVsPlane(p) != NoIntersect {
+		t.Error("Cylinder.CollideVsPlane() indicated a plane collided that should not have.")
+	}
+}
+
+func TestCylinderCollisionVsRay(t *testing.T) {
+	c := NewCylinder()
+	c.Radius = 1.0
+	c.HalfHeight = 2.0
+	c.SetOffset3f(0.0, 0.0, -10.0)
+
+	// hit the curved side
+	ray, _ := NewCollisionRay(mgl.Vec3{-5.0, 0.0, -10.0}, mgl.Vec3{1, 0, 0})
+	result, dist, normal := c.CollideVsRayWithNormal(ray)
+	if result != Intersect {
+		t.Fatal("Cylinder.CollideVsRayWithNormal() didn't hit the side of the cylinder.")
+	}
+	if !mgl.FloatEqualThreshold(dist, 4.0, 1e-4) {
+		t.Errorf("Cylinder.CollideVsRayWithNormal() returned the wrong distance to the side: %f", dist)
+	}
+	if !normal.ApproxEqualThreshold(mgl.Vec3{-1, 0, 0}, 1e-4) {
+		t.Errorf("Cylinder.CollideVsRayWithNormal() returned the wrong normal for the side: %v", normal)
+	}
+
+	// hit the top cap
+	ray, _ = NewCollisionRay(mgl.Vec3{0.5, 5.0, -10.0}, mgl.Vec3{0, -1, 0})
+	result, dist, normal = c.CollideVsRayWithNormal(ray)
+	if result != Intersect || !mgl.FloatEqualThreshold(dist, 3.0, 1e-4) {
+		t.Errorf("Cylinder.CollideVsRayWithNormal() didn't hit the top cap correctly: %d %f", result, dist)
+	}
+	if !normal.ApproxEqualThreshold(mgl.Vec3{0, 1, 0}, 1e-4) {
+		t.Errorf("Cylinder.CollideVsRayWithNormal() returned the wrong normal for the top cap: %v", normal)
+	}
+
+	// miss beside the cap
+	ray, _ = NewCollisionRay(mgl.Vec3{1.5, 5.0, -10.0}, mgl.Vec3{0, -1, 0})
+	if result, _ := c.CollideVsRay(ray); result != NoIntersect {
+		t.Error("Cylinder.CollideVsRay() hit when the ray should have passed beside it.")
+	}
+
+	// start inside the cylinder
+	ray, _ = NewCollisionRay(mgl.Vec3{0.0, 0.0, -10.0}, mgl.Vec3{1, 0, 0})
+	if result, dist := c.CollideVsRay(ray); result != Intersect || dist != 0.0 {
+		t.Errorf("Cylinder.CollideVsRay() should hit at zero distance from inside: %d %f", result, dist)
+	}
+
+	// lay the cylinder down so the cap faces along the x axis
+	c.SetOrientation(mgl.QuatRotate(mgl.DegToRad(90.0), mgl.Vec3{0, 0, 1}))
+	ray, _ = NewCollisionRay(mgl.Vec3{-5.0, 0.0, -10.0}, mgl.Vec3{1, 0, 0})
+	result, dist, normal = c.CollideVsRayWithNormal(ray)
+	if result != Intersect || !mgl.FloatEqualThreshold(dist, 3.0, 1e-4) {
+		t.Errorf("Cylinder.CollideVsRayWithNormal() didn't hit the cap of a rotated cylinder: %d %f", result, dist)
+	}
+	if normal.Sub(mgl.Vec3{-1, 0, 0}).Len() > 1e-4 {
+		t.Errorf("Cylinder.CollideVsRayWithNormal() returned the wrong normal for a rotated cap: %v", normal)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package debug
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/glider"
+)
+
+func TestGeometryShapes(t *testing.T) {
+	g := NewGeometry()
+
+	b := glider.NewAABBox()
+	b.Min = mgl.Vec3{-1, -1, -1}
+	b.Max = mgl.Vec3{1, 1, 1}
+	g.AddAABBox(b)
+	if len(g.Lines) != 12 {
+		t.Errorf("Geometry.AddAABBox() added %d lines instead of 12", len(g.Lines))
+	}
+
+	obb := glider.NewOBBox()
+	obb.HalfSize = mgl.Vec3{1, 2, 3}
+	obb.SetOrientation(mgl.QuatRotate(mgl.DegToRad(90.0), mgl.Vec3{0, 1, 0}))
+	g.AddOBBox(obb)
+	if len(g.Lines) != 24 {
+		t.Errorf("Geometry.AddOBBox() added %d lines instead of 12", len(g.Lines)-12)
+	}
+	for _, c := range obb.Corners() {
+		// the box's local Z axis is now along world X
+		abs := mgl.Vec3{fabs(c[0]), fabs(c[1]), fabs(c[2])}
+		if !abs.ApproxEqualThreshold(mgl.Vec3{3, 2, 1}, 1e-4) {
+			t.Errorf("OBBox.Corners() returned a corner that isn't on the rotated box: %v", c)
+		}
+	}
+
+	s := glider.NewSphere()
+	s.Radius = 2.0
+	s.SetOffset3f(5, 0, 0)
+	if !g.AddCollider(s) {
+		t.Fatal("Geometry.AddCollider() didn't know about a Sphere.")
+	}
+	tris := g.Triangles
+	if len(tris) == 0 {
+		t.Fatal("Geometry.AddCollider() didn't add any triangles for a Sphere.")
+	}
+	for _, tri := range tris {
+		for _, i := range tri {
+			if d := g.Vertices[i].Sub(mgl.Vec3{5, 0, 0}).Len(); !mgl.FloatEqualThreshold(d, 2.0, 1e-4) {
+				t.Fatalf("Geometry.AddSphere() added a vertex %f from the center", d)
+			}
+		}
+	}
+
+	p := glider.NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 3, 0})
+	before := len(g.Triangles)
+	g.AddPlane(p, mgl.Vec3{}, DefaultPlaneSize)
+	for _, tri := range g.Triangles[before:] {
+		for _, i := range tri {
+			if g.Vertices[i][1] != 3 {
+				t.Errorf("Geometry.AddPlane() added a vertex off of the plane: %v", g.Vertices[i])
+			}
+		}
+	}
+}
+
+func TestGeometryWriteOBJ(t *testing.T) {
+	g := NewGeometry()
+	ray, _ := glider.NewCollisionRay(mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 0, -1})
+	g.AddRay(ray, DefaultRayLength)
+	g.AddTriangle(mgl.Vec3{0, 0, 0}, mgl.Vec3{1, 0, 0}, mgl.Vec3{0, 1, 0})
+
+	var buf bytes.Buffer
+	if err := g.WriteOBJ(&buf); err != nil {
+		t.Fatalf("Geometry.WriteOBJ() returned an error: %v", err)
+	}
+	obj := buf.String()
+	for _, expected := range []string{"v 0 0 -100\n", "l 1 2\n", "f 3 4 5\n"} {
+		if !strings.Contains(obj, expected) {
+			t.Errorf("Geometry.WriteOBJ() output is missing %q:\n%s", expected, obj)
+		}
+	}
+}
+
+func TestGeometry2DWriteSVG(t *testing.T) {
+	tm := glider.NewTileMap(3, 2, 1.0)
+	tm.Set(0, 0, glider.TileSolid)
+	tm.Set(2, 1, glider.TileOneWay)
+
+	g := NewGeometry2D()
+	g.AddTileMap(tm)
+	sq := glider.NewAABSquare()
+	sq.Max = mgl.Vec2{0.5, 0.5}
+	g.AddAABSquare(sq)
+	if len(g.Rects) != 2 || len(g.Lines) != 1 {
+		t.Errorf("Geometry2D added %d rects and %d lines instead of 2 and 1", len(g.Rects), len(g.Lines))
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteSVG(&buf); err != nil {
+		t.Fatalf("Geometry2D.WriteSVG() returned an error: %v", err)
+	}
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg") || strings.Count(svg, "<rect") != 2 || strings.Count(svg, "<line") != 1 {
+		t.Errorf("Geometry2D.WriteSVG() wrote unexpected output:\n%s", svg)
+	}
+}
+
+func fabs(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,171 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package debug
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/glider"
+)
+
+// DebugDrawer is implemented by renderers that can overlay glider's view of
+// the world. Positions are in world space and colors are RGBA.
+type DebugDrawer interface {
+	DrawLine(a, b mgl.Vec3, color mgl.Vec4)
+	DrawPoint(p mgl.Vec3, color mgl.Vec4)
+	DrawText(p mgl.Vec3, text string, color mgl.Vec4)
+}
+
+// DrawOptions controls what DrawWorld draws and the colors used.
+type DrawOptions struct {
+	// Colliders draws the shape of every collider.
+	Colliders bool
+
+	// Bounds draws the bounds of every collider held by the broadphase.
+	Bounds bool
+
+	// Contacts draws a line between the colliders in every contact found by
+	// the last Step. World only keeps the pairs that touched, not where they
+	// touched, so the line joins the centers of the two colliders' bounds
+	// with a point halfway along it. Use DrawContact for real contact points.
+	Contacts bool
+
+	// Labels draws the handle and Tags of every collider at the center of
+	// its bounds. Colliders without finite bounds aren't labelled.
+	Labels bool
+
+	// ColliderColor is the color of the collider shapes.
+	ColliderColor mgl.Vec4
+
+	// BoundsColor is the color of the broadphase bounds.
+	BoundsColor mgl.Vec4
+
+	// ContactColor is the color of the contact lines and points.
+	ContactColor mgl.Vec4
+
+	// LabelColor is the color of the labels.
+	LabelColor mgl.Vec4
+}
+
+// DefaultDrawOptions draws everything.
+var DefaultDrawOptions = DrawOptions{
+	Colliders:     true,
+	Bounds:        true,
+	Contacts:      true,
+	Labels:        true,
+	ColliderColor: mgl.Vec4{0, 1, 0, 1},
+	BoundsColor:   mgl.Vec4{0.5, 0.5, 0.5, 1},
+	ContactColor:  mgl.Vec4{1, 0, 0, 1},
+	LabelColor:    mgl.Vec4{1, 1, 1, 1},
+}
+
+// DrawGeometry draws the lines of the geometry and the edges of its triangles.
+func DrawGeometry(d DebugDrawer, g *Geometry, color mgl.Vec4) {
+	for _, l := range g.Lines {
+		d.DrawLine(g.Vertices[l[0]], g.Vertices[l[1]], color)
+	}
+	for _, t := range g.Triangles {
+		v0, v1, v2 := g.Vertices[t[0]], g.Vertices[t[1]], g.Vertices[t[2]]
+		d.DrawLine(v0, v1, color)
+		d.DrawLine(v1, v2, color)
+		d.DrawLine(v2, v0, color)
+	}
+}
+
+// DrawCollider draws the shape of any of the colliders from the glider
+// package. The second return value is false if the collider's type isn't
+// known and nothing was drawn.
+func DrawCollider(d DebugDrawer, c glider.Collider, color mgl.Vec4) bool {
+	g := NewGeometry()
+	if !g.AddCollider(c) {
+		return false
+	}
+	DrawGeometry(d, g, color)
+	return true
+}
+
+// DrawBounds draws the twelve edges of the bounds.
+func DrawBounds(d DebugDrawer, b glider.Bounds, color mgl.Vec4) {
+	g := NewGeometry()
+	g.AddAABBox(&glider.AABBox{Min: b.Min, Max: b.Max})
+	DrawGeometry(d, g, color)
+}
+
+// DrawContact draws a point at the contact and a line showing its normal.
+func DrawContact(d DebugDrawer, point, normal mgl.Vec3, color mgl.Vec4) {
+	d.DrawPoint(point, color)
+	d.DrawLine(point, point.Add(normal.Mul(DefaultNormalLength)), color)
+}
+
+// boundsCenter returns the center of the bounds. The second return value is
+// false if the bounds are infinite, like those of colliders that glider
+// can't bound, and so have no center.
+func boundsCenter(b glider.Bounds) (mgl.Vec3, bool) {
+	center := b.Min.Add(b.Max).Mul(0.5)
+	for _, v := range center {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return center, false
+		}
+	}
+	return center, true
+}
+
+// DrawWorld draws the colliders, broadphase bounds, contacts from the last
+// Step and labels of a world. A nil opts uses DefaultDrawOptions. The world's
+// handles are gathered in handles, which is returned so that it can be
+// passed back in the next frame instead of allocating a new one.
+func DrawWorld(d DebugDrawer, w *glider.World, opts *DrawOptions, handles []glider.Handle) []glider.Handle {
+	if opts == nil {
+		opts = &DefaultDrawOptions
+	}
+
+	handles = w.Handles(handles[:0])
+	if opts.Colliders || opts.Labels {
+		for _, h := range handles {
+			c, _ := w.Collider(h)
+			if opts.Colliders {
+				DrawCollider(d, c, opts.ColliderColor)
+			}
+			if opts.Labels {
+				center, okay := boundsCenter(glider.ColliderBounds(c))
+				if !okay {
+					continue
+				}
+				label := fmt.Sprintf("#%d", h)
+				if tags := glider.ColliderTags(c); len(tags) > 0 {
+					label += " " + strings.Join(tags, ",")
+				}
+				d.DrawText(center, label, opts.LabelColor)
+			}
+		}
+	}
+
+	if walker, okay := w.Broadphase().(glider.BroadphaseWalker); okay && opts.Bounds {
+		walker.WalkBounds(func(h glider.Handle, b glider.Bounds) {
+			DrawBounds(d, b, opts.BoundsColor)
+		})
+	}
+
+	if opts.Contacts {
+		for _, pair := range w.Contacts() {
+			ca, okayA := w.Collider(pair.A)
+			cb, okayB := w.Collider(pair.B)
+			if !okayA || !okayB {
+				continue
+			}
+			pa, okayA := boundsCenter(glider.ColliderBounds(ca))
+			pb, okayB := boundsCenter(glider.ColliderBounds(cb))
+			if !okayA || !okayB {
+				continue
+			}
+			d.DrawLine(pa, pb, opts.ContactColor)
+			d.DrawPoint(pa.Add(pb).Mul(0.5), opts.ContactColor)
+		}
+	}
+
+	return handles
+}
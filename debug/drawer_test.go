@@ -0,0 +1,108 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package debug
+
+import (
+	"strings"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/glider"
+)
+
+// countingDrawer counts the draw calls made by color.
+type countingDrawer struct {
+	lines  map[mgl.Vec4]int
+	points int
+	texts  []string
+}
+
+func newCountingDrawer() *countingDrawer {
+	return &countingDrawer{lines: make(map[mgl.Vec4]int)}
+}
+
+func (cd *countingDrawer) DrawLine(a, b mgl.Vec3, color mgl.Vec4) { cd.lines[color]++ }
+func (cd *countingDrawer) DrawPoint(p mgl.Vec3, color mgl.Vec4)   { cd.points++ }
+func (cd *countingDrawer) DrawText(p mgl.Vec3, text string, color mgl.Vec4) {
+	cd.texts = append(cd.texts, text)
+}
+
+// everywhere is a collider without bounds that touches everything.
+type everywhere struct{}
+
+func (e everywhere) CollideVsSphere(sphere *glider.Sphere) int { return glider.Intersect }
+func (e everywhere) CollideVsAABBox(box *glider.AABBox) int    { return glider.Intersect }
+func (e everywhere) CollideVsPlane(plane *glider.Plane) int    { return glider.Intersect }
+func (e everywhere) CollideVsRay(ray *glider.CollisionRay) (int, float32) {
+	return glider.Intersect, 0
+}
+func (e everywhere) SetOffset(offset *mgl.Vec3)  {}
+func (e everywhere) SetOffset3f(x, y, z float32) {}
+
+func TestDrawWorld(t *testing.T) {
+	w := glider.NewWorld(glider.NewSweepAndPruneBroadphase())
+
+	b1 := glider.NewAABBox()
+	b1.Min = mgl.Vec3{-1, -1, -1}
+	b1.Max = mgl.Vec3{1, 1, 1}
+	b1.Tags = []string{"crate"}
+	w.Add(b1)
+
+	b2 := glider.NewAABBox()
+	b2.Min = mgl.Vec3{-1, -1, -1}
+	b2.Max = mgl.Vec3{1, 1, 1}
+	b2.SetOffset3f(1.5, 0, 0)
+	w.Add(b2)
+	w.Step()
+
+	cd := newCountingDrawer()
+	handles := DrawWorld(cd, w, nil, nil)
+
+	opts := DefaultDrawOptions
+	if cd.lines[opts.ColliderColor] != 24 {
+		t.Errorf("DrawWorld() drew %d collider lines instead of 24", cd.lines[opts.ColliderColor])
+	}
+	if cd.lines[opts.BoundsColor] != 24 {
+		t.Errorf("DrawWorld() drew %d bounds lines instead of 24", cd.lines[opts.BoundsColor])
+	}
+	if cd.lines[opts.ContactColor] != 1 || cd.points != 1 {
+		t.Errorf("DrawWorld() drew %d contact lines and %d points instead of 1", cd.lines[opts.ContactColor], cd.points)
+	}
+	if len(cd.texts) != 2 || !strings.Contains(cd.texts[0], "crate") {
+		t.Errorf("DrawWorld() drew the wrong labels: %v", cd.texts)
+	}
+
+	if len(handles) != 2 {
+		t.Errorf("DrawWorld() returned %v instead of the two handles", handles)
+	}
+
+	// only draw the colliders, reusing the handles from the last frame
+	cd = newCountingDrawer()
+	opts = DrawOptions{Colliders: true, ColliderColor: mgl.Vec4{0, 0, 1, 1}}
+	if reused := DrawWorld(cd, w, &opts, handles); len(reused) != 2 || &reused[0] != &handles[0] {
+		t.Errorf("DrawWorld() returned %v instead of reusing the handles", reused)
+	}
+	if len(cd.lines) != 1 || cd.lines[opts.ColliderColor] != 24 || len(cd.texts) != 0 {
+		t.Errorf("DrawWorld() drew more than the colliders: %v %v", cd.lines, cd.texts)
+	}
+}
+
+func TestDrawWorldUnbounded(t *testing.T) {
+	w := glider.NewWorld(glider.NewBruteForceBroadphase())
+	b := glider.NewAABBox()
+	b.Min = mgl.Vec3{-1, -1, -1}
+	b.Max = mgl.Vec3{1, 1, 1}
+	w.Add(b)
+	w.Add(everywhere{})
+	if len(w.Step()) != 1 {
+		t.Fatal("World.Step() didn't find the contact with the unbounded collider")
+	}
+
+	cd := newCountingDrawer()
+	opts := DrawOptions{Contacts: true, Labels: true}
+	DrawWorld(cd, w, &opts, nil)
+	if len(cd.texts) != 1 || cd.points != 0 || len(cd.lines) != 0 {
+		t.Errorf("DrawWorld() drew an unbounded collider: %v %d %v", cd.texts, cd.points, cd.lines)
+	}
+}
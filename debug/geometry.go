@@ -0,0 +1,296 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+/*
+
+Package debug turns glider's collision shapes into simple line and triangle
+geometry that can be exported to files and viewed in external tools.
+
+*/
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/glider"
+)
+
+const (
+	// DefaultSegments is how many segments are used around round shapes.
+	DefaultSegments = 16
+
+	// DefaultRayLength is how long unbounded rays are drawn.
+	DefaultRayLength = 100.0
+
+	// DefaultNormalLength is how long contact normals are drawn.
+	DefaultNormalLength = 0.5
+
+	// DefaultPlaneSize is the length of the sides of the quad drawn for planes.
+	DefaultPlaneSize = 10.0
+)
+
+// Geometry is a collection of world-space lines and triangles built from
+// collision shapes.
+type Geometry struct {
+	// Vertices holds the positions used by Lines and Triangles.
+	Vertices []mgl.Vec3
+
+	// Lines holds pairs of indexes into Vertices.
+	Lines [][2]int
+
+	// Triangles holds sets of three indexes into Vertices.
+	Triangles [][3]int
+}
+
+// NewGeometry creates a new, empty Geometry object.
+func NewGeometry() *Geometry {
+	return new(Geometry)
+}
+
+// addVertex adds the position to Vertices and returns its index.
+func (g *Geometry) addVertex(v mgl.Vec3) int {
+	g.Vertices = append(g.Vertices, v)
+	return len(g.Vertices) - 1
+}
+
+// AddLine adds a line from a to b.
+func (g *Geometry) AddLine(a, b mgl.Vec3) {
+	g.Lines = append(g.Lines, [2]int{g.addVertex(a), g.addVertex(b)})
+}
+
+// AddTriangle adds a triangle with the vertices a, b and c.
+func (g *Geometry) AddTriangle(a, b, c mgl.Vec3) {
+	g.Triangles = append(g.Triangles, [3]int{g.addVertex(a), g.addVertex(b), g.addVertex(c)})
+}
+
+// addBoxCorners adds the twelve edges of a box from its corners, where bit 0
+// of a corner's index selects the +X side, bit 1 the +Y side and bit 2 the
+// +Z side.
+func (g *Geometry) addBoxCorners(corners [8]mgl.Vec3) {
+	for c := 0; c < 8; c++ {
+		for axis := uint(0); axis < 3; axis++ {
+			if c&(1<<axis) == 0 {
+				g.AddLine(corners[c], corners[c|(1<<axis)])
+			}
+		}
+	}
+}
+
+// AddAABBox adds a wireframe of the box.
+func (g *Geometry) AddAABBox(b *glider.AABBox) {
+	min := b.Min.Add(b.Offset)
+	max := b.Max.Add(b.Offset)
+	var corners [8]mgl.Vec3
+	for c := 0; c < 8; c++ {
+		for i := uint(0); i < 3; i++ {
+			if c&(1<<i) != 0 {
+				corners[c][i] = max[i]
+			} else {
+				corners[c][i] = min[i]
+			}
+		}
+	}
+	g.addBoxCorners(corners)
+}
+
+// AddOBBox adds a wireframe of the box.
+func (g *Geometry) AddOBBox(b *glider.OBBox) {
+	g.addBoxCorners(b.Corners())
+}
+
+// addEllipsoid adds a latitude and longitude tessellation of a unit sphere
+// that is scaled by radii, rotated by q and moved to center.
+func (g *Geometry) addEllipsoid(center, radii mgl.Vec3, q mgl.Quat, segments int) {
+	if segments < 4 {
+		segments = 4
+	}
+	rings := segments / 2
+	point := func(ring, seg int) mgl.Vec3 {
+		phi := math.Pi * float64(ring) / float64(rings)
+		theta := 2.0 * math.Pi * float64(seg) / float64(segments)
+		p := mgl.Vec3{
+			float32(math.Sin(phi)*math.Cos(theta)) * radii[0],
+			float32(math.Cos(phi)) * radii[1],
+			float32(math.Sin(phi)*math.Sin(theta)) * radii[2],
+		}
+		return center.Add(q.Rotate(p))
+	}
+
+	for ring := 0; ring < rings; ring++ {
+		for seg := 0; seg < segments; seg++ {
+			p00 := point(ring, seg)
+			p01 := point(ring, seg+1)
+			p10 := point(ring+1, seg)
+			p11 := point(ring+1, seg+1)
+			if ring > 0 {
+				g.AddTriangle(p00, p01, p10)
+			}
+			if ring < rings-1 {
+				g.AddTriangle(p01, p11, p10)
+			}
+		}
+	}
+}
+
+// AddSphere adds a tessellated sphere made from segments around its equator.
+func (g *Geometry) AddSphere(s *glider.Sphere, segments int) {
+	r := s.Radius
+	g.addEllipsoid(s.Center.Add(s.Offset), mgl.Vec3{r, r, r}, mgl.QuatIdent(), segments)
+}
+
+// AddEllipsoid adds a tessellated ellipsoid made from segments around its
+// equator.
+func (g *Geometry) AddEllipsoid(e *glider.Ellipsoid, segments int) {
+	g.addEllipsoid(e.Center.Add(e.Offset), e.Radii, e.GetOrientation(), segments)
+}
+
+// addRings adds a shape made from rings around the local Y axis at the
+// given heights and radii, rotated by q and moved to origin. Rings with no
+// radius become a single point.
+func (g *Geometry) addRings(origin mgl.Vec3, q mgl.Quat, heights, radii []float32, segments int) {
+	if segments < 3 {
+		segments = 3
+	}
+	point := func(ring, seg int) mgl.Vec3 {
+		theta := 2.0 * math.Pi * float64(seg) / float64(segments)
+		p := mgl.Vec3{
+			float32(math.Cos(theta)) * radii[ring],
+			heights[ring],
+			float32(math.Sin(theta)) * radii[ring],
+		}
+		return origin.Add(q.Rotate(p))
+	}
+
+	last := len(heights) - 1
+	for seg := 0; seg < segments; seg++ {
+		// the caps at either end
+		for _, ring := range [2]int{0, last} {
+			if radii[ring] > 0 {
+				center := origin.Add(q.Rotate(mgl.Vec3{0, heights[ring], 0}))
+				g.AddTriangle(center, point(ring, seg), point(ring, seg+1))
+			}
+		}
+
+		// the sides between the rings
+		for ring := 0; ring < last; ring++ {
+			g.AddTriangle(point(ring, seg), point(ring+1, seg), point(ring+1, seg+1))
+			g.AddTriangle(point(ring, seg), point(ring+1, seg+1), point(ring, seg+1))
+		}
+	}
+}
+
+// AddCylinder adds a tessellated cylinder made from segments around its axis.
+func (g *Geometry) AddCylinder(c *glider.Cylinder, segments int) {
+	g.addRings(c.Center.Add(c.Offset), c.GetOrientation(),
+		[]float32{-c.HalfHeight, c.HalfHeight}, []float32{c.Radius, c.Radius}, segments)
+}
+
+// AddCone adds a tessellated cone made from segments around its axis.
+func (g *Geometry) AddCone(c *glider.Cone, segments int) {
+	g.addRings(c.Apex.Add(c.Offset), c.GetOrientation(),
+		[]float32{0, c.Height}, []float32{0, c.Radius}, segments)
+}
+
+// AddPlane adds a square quad size units across lying on the plane and
+// centered on the point of the plane closest to center, along with a line
+// showing the plane's normal.
+func (g *Geometry) AddPlane(p *glider.Plane, center mgl.Vec3, size float32) {
+	n := p.Normal.Normalize()
+	onPlane := center.Sub(n.Mul(p.Distance(center) / p.Normal.Len()))
+
+	// build two directions across the plane
+	up := mgl.Vec3{0, 1, 0}
+	if math.Abs(float64(n.Dot(up))) > 0.9 {
+		up = mgl.Vec3{1, 0, 0}
+	}
+	u := n.Cross(up).Normalize().Mul(size * 0.5)
+	v := n.Cross(u)
+
+	c0 := onPlane.Sub(u).Sub(v)
+	c1 := onPlane.Add(u).Sub(v)
+	c2 := onPlane.Add(u).Add(v)
+	c3 := onPlane.Sub(u).Add(v)
+	g.AddTriangle(c0, c1, c2)
+	g.AddTriangle(c0, c2, c3)
+	g.AddLine(onPlane, onPlane.Add(n.Mul(size*0.1)))
+}
+
+// AddTriangleSet adds every triangle in the set, such as a Mesh or Heightfield.
+func (g *Geometry) AddTriangleSet(ts glider.TriangleSet) {
+	count := ts.TriangleCount()
+	for i := 0; i < count; i++ {
+		g.AddTriangle(ts.Triangle(i))
+	}
+}
+
+// AddVoxelGrid adds wireframes of the solid voxels merged into boxes.
+func (g *Geometry) AddVoxelGrid(vg *glider.VoxelGrid) {
+	for _, b := range glider.MergeVoxels(vg) {
+		g.AddAABBox(b)
+	}
+}
+
+// AddRay adds a line along the ray. Rays with a MaxDistance are drawn that
+// long and unbounded rays are drawn length units long.
+func (g *Geometry) AddRay(r *glider.CollisionRay, length float32) {
+	if r.MaxDistance > 0 {
+		length = r.MaxDistance
+	}
+	g.AddLine(r.Origin, r.Origin.Add(r.GetDirection().Mul(length)))
+}
+
+// AddContact adds a line length units long showing the normal at a contact
+// point.
+func (g *Geometry) AddContact(point, normal mgl.Vec3, length float32) {
+	g.AddLine(point, point.Add(normal.Mul(length)))
+}
+
+// AddCollider adds any of the colliders from the glider package using the
+// Default* settings. The second return value is false if the collider's
+// type isn't known.
+func (g *Geometry) AddCollider(c glider.Collider) bool {
+	switch s := c.(type) {
+	case *glider.AABBox:
+		g.AddAABBox(s)
+	case *glider.Sphere:
+		g.AddSphere(s, DefaultSegments)
+	case *glider.Ellipsoid:
+		g.AddEllipsoid(s, DefaultSegments)
+	case *glider.Cylinder:
+		g.AddCylinder(s, DefaultSegments)
+	case *glider.Cone:
+		g.AddCone(s, DefaultSegments)
+	case *glider.Triangle:
+		g.AddTriangle(s.V0.Add(s.Offset), s.V1.Add(s.Offset), s.V2.Add(s.Offset))
+	case *glider.VoxelGrid:
+		g.AddVoxelGrid(s)
+	case glider.TriangleSet:
+		g.AddTriangleSet(s)
+	default:
+		return false
+	}
+	return true
+}
+
+// WriteOBJ writes the geometry out as a Wavefront OBJ file with the lines as
+// "l" elements and the triangles as "f" elements.
+func (g *Geometry) WriteOBJ(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# glider debug geometry")
+	for _, v := range g.Vertices {
+		fmt.Fprintf(bw, "v %g %g %g\n", v[0], v[1], v[2])
+	}
+
+	// OBJ indexes start at one
+	for _, l := range g.Lines {
+		fmt.Fprintf(bw, "l %d %d\n", l[0]+1, l[1]+1)
+	}
+	for _, t := range g.Triangles {
+		fmt.Fprintf(bw, "f %d %d %d\n", t[0]+1, t[1]+1, t[2]+1)
+	}
+	return bw.Flush()
+}
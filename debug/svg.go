@@ -0,0 +1,123 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/glider"
+)
+
+// Geometry2D is a collection of world-space 2d rectangles and lines built
+// from collision shapes.
+type Geometry2D struct {
+	// Rects holds the minimum and maximum corners of axis aligned rectangles.
+	Rects [][2]mgl.Vec2
+
+	// Lines holds the start and end of each line.
+	Lines [][2]mgl.Vec2
+}
+
+// NewGeometry2D creates a new, empty Geometry2D object.
+func NewGeometry2D() *Geometry2D {
+	return new(Geometry2D)
+}
+
+// AddRect adds a rectangle from its minimum and maximum corners.
+func (g *Geometry2D) AddRect(min, max mgl.Vec2) {
+	g.Rects = append(g.Rects, [2]mgl.Vec2{min, max})
+}
+
+// AddLine adds a line from a to b.
+func (g *Geometry2D) AddLine(a, b mgl.Vec2) {
+	g.Lines = append(g.Lines, [2]mgl.Vec2{a, b})
+}
+
+// AddAABSquare adds a rectangle for the square.
+func (g *Geometry2D) AddAABSquare(s *glider.AABSquare) {
+	g.AddRect(s.Min.Add(s.Offset), s.Max.Add(s.Offset))
+}
+
+// AddTileMap adds a rectangle for every solid tile and a line along the top
+// of every one-way tile.
+func (g *Geometry2D) AddTileMap(tm *glider.TileMap) {
+	for y := 0; y < tm.Height; y++ {
+		for x := 0; x < tm.Width; x++ {
+			min := mgl.Vec2{float32(x), float32(y)}.Mul(tm.TileSize).Add(tm.Offset)
+			max := min.Add(mgl.Vec2{tm.TileSize, tm.TileSize})
+			switch tm.Get(x, y) {
+			case glider.TileSolid:
+				g.AddRect(min, max)
+			case glider.TileOneWay:
+				g.AddLine(mgl.Vec2{min[0], max[1]}, max)
+			}
+		}
+	}
+}
+
+// AddRay2D adds a line along the ray. Rays with a MaxDistance are drawn that
+// long and unbounded rays are drawn length units long.
+func (g *Geometry2D) AddRay2D(r *glider.CollisionRay2D, length float32) {
+	if r.MaxDistance > 0 {
+		length = r.MaxDistance
+	}
+	g.AddLine(r.Origin, r.Origin.Add(r.GetDirection().Mul(length)))
+}
+
+// bounds returns the minimum and maximum corners of everything in the
+// geometry.
+func (g *Geometry2D) bounds() (mgl.Vec2, mgl.Vec2) {
+	inf := float32(math.Inf(1))
+	min := mgl.Vec2{inf, inf}
+	max := mgl.Vec2{-inf, -inf}
+	expand := func(p mgl.Vec2) {
+		for i := 0; i < 2; i++ {
+			min[i] = float32(math.Min(float64(min[i]), float64(p[i])))
+			max[i] = float32(math.Max(float64(max[i]), float64(p[i])))
+		}
+	}
+	for _, r := range g.Rects {
+		expand(r[0])
+		expand(r[1])
+	}
+	for _, l := range g.Lines {
+		expand(l[0])
+		expand(l[1])
+	}
+	if min[0] > max[0] {
+		return mgl.Vec2{}, mgl.Vec2{1, 1}
+	}
+	return min, max
+}
+
+// WriteSVG writes the geometry out as an SVG image. The Y axis is flipped so
+// that it points up like it does in the glider package.
+func (g *Geometry2D) WriteSVG(w io.Writer) error {
+	min, max := g.bounds()
+	size := max.Sub(min)
+	margin := float32(math.Max(float64(size[0]), float64(size[1]))) * 0.05
+	if margin == 0 {
+		margin = 1
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"%g %g %g %g\">\n",
+		min[0]-margin, -max[1]-margin, size[0]+margin*2, size[1]+margin*2)
+	fmt.Fprintln(bw, "<g transform=\"scale(1,-1)\" fill=\"none\" stroke=\"black\" stroke-width=\"1\" vector-effect=\"non-scaling-stroke\">")
+	for _, r := range g.Rects {
+		fmt.Fprintf(bw, "<rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" vector-effect=\"non-scaling-stroke\"/>\n",
+			r[0][0], r[0][1], r[1][0]-r[0][0], r[1][1]-r[0][1])
+	}
+	for _, l := range g.Lines {
+		fmt.Fprintf(bw, "<line x1=\"%g\" y1=\"%g\" x2=\"%g\" y2=\"%g\" stroke=\"red\" vector-effect=\"non-scaling-stroke\"/>\n",
+			l[0][0], l[0][1], l[1][0], l[1][1])
+	}
+	fmt.Fprintln(bw, "</g>")
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
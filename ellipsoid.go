@@ -0,0 +1,438 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// slideVeryCloseDistance is how far, in ellipsoid space, a sliding
+	// ellipsoid is kept away from the surfaces it hits.
+	slideVeryCloseDistance = 0.005
+)
+
+// Ellipsoid is defined by a center point, a radius along each of its local
+// axes and an orientation. It fits tall or wide characters much better
+// than a Sphere.
+type Ellipsoid struct {
+	// Center is the center point of the ellipsoid, in local space (model-space in 3d graphics)
+	Center mgl.Vec3
+
+	// Offset is the world-space location of the that can be considered an offset to Center
+	Offset mgl.Vec3
+
+	// Radii holds the radius of the ellipsoid along each of its local axes.
+	Radii mgl.Vec3
+
+	orientation mgl.Quat
+
+	// Tags provides a way to label an ellipsoid in a custom application
+	// (e.g. labelling a collision as "player" or "npc").
+	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// ellipsoid is hit, such as the game object that owns it.
+	UserData interface{}
+
+	// Material describes the surface of the ellipsoid.
+	Material Material
+}
+
+// SweepHit describes the first contact made by a shape moving along a
+// velocity vector.
+type SweepHit struct {
+	// Time is the fraction of the velocity vector that was travelled
+	// before contact, from 0 to 1.
+	Time float32
+
+	// Point is the world-space point of contact.
+	Point mgl.Vec3
+
+	// Normal is the world-space normal of the contact which points away
+	// from the surface that was hit.
+	Normal mgl.Vec3
+}
+
+// NewEllipsoid creates a new Ellipsoid object.
+func NewEllipsoid() *Ellipsoid {
+	e := new(Ellipsoid)
+	e.orientation = mgl.QuatIdent()
+	return e
+}
+
+// SetOffset changes the offset of the collision object.
+func (e *Ellipsoid) SetOffset(offset *mgl.Vec3) {
+	e.Offset = *offset
+}
+
+// SetOffset3f changes the offset of the collision object.
+func (e *Ellipsoid) SetOffset3f(x, y, z float32) {
+	e.Offset[0] = x
+	e.Offset[1] = y
+	e.Offset[2] = z
+}
+
+// SetOrientation sets the rotation of the ellipsoid.
+func (e *Ellipsoid) SetOrientation(q mgl.Quat) {
+	e.orientation = q
+}
+
+// GetOrientation gets the rotation of the ellipsoid.
+func (e *Ellipsoid) GetOrientation() mgl.Quat {
+	return e.rotation()
+}
+
+// Validate checks the Ellipsoid for non-finite values, negative radii and
+// an orientation that isn't a unit quaternion.
+func (e *Ellipsoid) Validate() error {
+	if err := validateFinite("Ellipsoid", "Center", e.Center[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("Ellipsoid", "Offset", e.Offset[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("Ellipsoid", "Radii", e.Radii[:]); err != nil {
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		if e.Radii[i] < 0 {
+			return &ShapeError{Shape: "Ellipsoid", Field: "Radii", Err: ErrNegativeRadius}
+		}
+		if e.Radii[i] == 0 {
+			return &ShapeError{Shape: "Ellipsoid", Field: "Radii", Err: ErrZeroRadius}
+		}
+	}
+	return validateOrientation("Ellipsoid", e.rotation())
+}
+
+// rotation returns the orientation of the ellipsoid.
+func (e *Ellipsoid) rotation() mgl.Quat {
+	return orientationOrIdent(e.orientation)
+}
+
+// worldCenter returns the world-space center of the ellipsoid.
+func (e *Ellipsoid) worldCenter() mgl.Vec3 {
+	return e.Center.Add(e.Offset)
+}
+
+// toEllipsoidSpace transforms a world-space direction into the space where
+// the ellipsoid is a unit sphere.
+func (e *Ellipsoid) toEllipsoidSpace(v mgl.Vec3) mgl.Vec3 {
+	local := e.rotation().Conjugate().Rotate(v)
+	return mgl.Vec3{local[0] / e.Radii[0], local[1] / e.Radii[1], local[2] / e.Radii[2]}
+}
+
+// fromEllipsoidSpace transforms a direction in the space where the
+// ellipsoid is a unit sphere back into world space.
+func (e *Ellipsoid) fromEllipsoidSpace(v mgl.Vec3) mgl.Vec3 {
+	local := mgl.Vec3{v[0] * e.Radii[0], v[1] * e.Radii[1], v[2] * e.Radii[2]}
+	return e.rotation().Rotate(local)
+}
+
+// normalFromEllipsoidSpace transforms a surface normal in the space where the
+// ellipsoid is a unit sphere back into a normalized world-space normal.
+func (e *Ellipsoid) normalFromEllipsoidSpace(n mgl.Vec3) mgl.Vec3 {
+	local := mgl.Vec3{n[0] / e.Radii[0], n[1] / e.Radii[1], n[2] / e.Radii[2]}
+	world := e.rotation().Rotate(local)
+	if l := world.Len(); l > 0 {
+		world = world.Mul(1.0 / l)
+	}
+	return world
+}
+
+// support returns the point on the ellipsoid furthest along d.
+func (e *Ellipsoid) support(d mgl.Vec3) mgl.Vec3 {
+	q := e.rotation()
+	local := q.Conjugate().Rotate(d)
+	scaled := mgl.Vec3{local[0] * e.Radii[0], local[1] * e.Radii[1], local[2] * e.Radii[2]}
+	l := scaled.Len()
+	if l == 0 {
+		return e.worldCenter()
+	}
+	p := mgl.Vec3{scaled[0] * e.Radii[0] / l, scaled[1] * e.Radii[1] / l, scaled[2] * e.Radii[2] / l}
+	return e.worldCenter().Add(q.Rotate(p))
+}
+
+// CollideVsSphere tests a collision between an ellipsoid and a sphere.
+func (e *Ellipsoid) CollideVsSphere(s *Sphere) int {
+	if gjkIntersect(e, s) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsAABBox tests a collision between an ellipsoid and an AABBox.
+func (e *Ellipsoid) CollideVsAABBox(b *AABBox) int {
+	if gjkIntersect(e, b) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsOBBox tests a collision between an ellipsoid and an OBBox.
+func (e *Ellipsoid) CollideVsOBBox(obb *OBBox) int {
+	if gjkIntersect(e, obb) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsEllipsoid tests a collision between two ellipsoids.
+func (e *Ellipsoid) CollideVsEllipsoid(e2 *Ellipsoid) int {
+	if gjkIntersect(e, e2) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsPlane tests a collision between an ellipsoid and a plane. Like
+// the other shapes, the ellipsoid only fails to intersect if it lies
+// completely behind the plane.
+func (e *Ellipsoid) CollideVsPlane(p *Plane) int {
+	return convexVsPlane(e, p)
+}
+
+// CollideVsRay tests a collision between an ellipsoid and a ray and returns
+// the distance along the ray to the surface of the ellipsoid. If the ray
+// starts inside the ellipsoid the distance is zero.
+func (e *Ellipsoid) CollideVsRay(ray *CollisionRay) (int, float32) {
+	if !ray.valid() {
+		return NoIntersect, 0.0
+	}
+
+	// in ellipsoid space this becomes a ray vs unit sphere test and
+	// because the transform is linear the distances stay the same.
+	origin := e.toEllipsoidSpace(ray.Origin.Sub(e.worldCenter()))
+	dir := e.toEllipsoidSpace(ray.direction)
+
+	c := origin.Dot(origin) - 1.0
+	if c <= 0 {
+		return Intersect, 0.0
+	}
+
+	a := dir.Dot(dir)
+	b := origin.Dot(dir)
+	if b >= 0 {
+		return NoIntersect, 0.0
+	}
+
+	disc := b*b - a*c
+	if disc < 0 {
+		return NoIntersect, 0.0
+	}
+
+	dist := (-b - float32(math.Sqrt(float64(disc)))) / a
+	if !ray.inRange(dist) {
+		return NoIntersect, dist
+	}
+	return Intersect, dist
+}
+
+// Sweep moves the ellipsoid along velocity and returns the first contact
+// it would make with the triangles, using the swept sphere in ellipsoid
+// space approach from Kasper Fauerby's "Improved Collision detection and
+// Response".
+func (e *Ellipsoid) Sweep(velocity mgl.Vec3, triangles TriangleSet) (int, SweepHit) {
+	var hit SweepHit
+	base := e.toEllipsoidSpace(e.worldCenter())
+	vel := e.toEllipsoidSpace(velocity)
+
+	found, t, point := e.sweepEllipsoidSpace(base, vel, triangles)
+	if !found {
+		return NoIntersect, hit
+	}
+
+	hit.Time = t
+	hit.Point = e.fromEllipsoidSpace(point)
+	hit.Normal = e.normalFromEllipsoidSpace(base.Add(vel.Mul(t)).Sub(point))
+	return Intersect, hit
+}
+
+// CollideAndSlide moves the ellipsoid along velocity through the triangles,
+// sliding along any surfaces it hits like a character controller would,
+// and returns the new value for Offset. At most maxIterations slides are
+// performed. The ellipsoid itself is not modified.
+func (e *Ellipsoid) CollideAndSlide(velocity mgl.Vec3, triangles TriangleSet, maxIterations int) mgl.Vec3 {
+	pos := e.toEllipsoidSpace(e.worldCenter())
+	vel := e.toEllipsoidSpace(velocity)
+
+	for i := 0; i < maxIterations; i++ {
+		found, t, point := e.sweepEllipsoidSpace(pos, vel, triangles)
+		if !found {
+			pos = pos.Add(vel)
+			break
+		}
+
+		destination := pos.Add(vel)
+		velLen := vel.Len()
+		velDir := vel.Mul(1.0 / velLen)
+
+		// only move up to just before the contact and move the contact
+		// point back by the same amount so that we don't get stuck
+		dist := velLen * t
+		if dist >= slideVeryCloseDistance {
+			pos = pos.Add(velDir.Mul(dist - slideVeryCloseDistance))
+			point = point.Sub(velDir.Mul(slideVeryCloseDistance))
+		}
+
+		// project the rest of the movement onto the sliding plane
+		slideNormal := pos.Sub(point).Normalize()
+		slidePlane := NewPlaneFromNormalAndPoint(slideNormal, point)
+		newDestination := destination.Sub(slideNormal.Mul(slidePlane.Distance(destination)))
+		vel = newDestination.Sub(point)
+		if vel.Len() < slideVeryCloseDistance {
+			break
+		}
+	}
+
+	center := e.fromEllipsoidSpace(pos)
+	return center.Sub(e.Center)
+}
+
+// sweepEllipsoidSpace finds the earliest collision of a unit sphere at base
+// moving along vel against the triangles transformed into ellipsoid space.
+func (e *Ellipsoid) sweepEllipsoidSpace(base, vel mgl.Vec3, triangles TriangleSet) (bool, float32, mgl.Vec3) {
+	found := false
+	nearest := float32(1.0)
+	var nearestPoint mgl.Vec3
+
+	triCount := triangles.TriangleCount()
+	for i := 0; i < triCount; i++ {
+		v0, v1, v2 := triangles.Triangle(i)
+		p0 := e.toEllipsoidSpace(v0)
+		p1 := e.toEllipsoidSpace(v1)
+		p2 := e.toEllipsoidSpace(v2)
+		hit, t, point := sweepUnitSphereVsTriangle(base, vel, p0, p1, p2, nearest)
+		if hit {
+			found = true
+			nearest = t
+			nearestPoint = point
+		}
+	}
+
+	return found, nearest, nearestPoint
+}
+
+// sweepUnitSphereVsTriangle sweeps a unit sphere at base along vel and returns
+// the time and point of its first contact with the triangle if it happens
+// before maxT. Triangles are treated as two-sided.
+func sweepUnitSphereVsTriangle(base, vel, p0, p1, p2 mgl.Vec3, maxT float32) (bool, float32, mgl.Vec3) {
+	normal := p1.Sub(p0).Cross(p2.Sub(p0))
+	nLen := normal.Len()
+	if nLen == 0 {
+		return false, 0, mgl.Vec3{}
+	}
+	normal = normal.Mul(1.0 / nLen)
+
+	// make the triangle face the sphere
+	signedDist := normal.Dot(base.Sub(p0))
+	if signedDist < 0 {
+		normal = normal.Mul(-1.0)
+		signedDist = -signedDist
+	}
+
+	// find when the sphere touches the plane of the triangle
+	nDotV := normal.Dot(vel)
+	var t0 float32
+	embedded := false
+	if nDotV > -rayEpsilon && nDotV < rayEpsilon {
+		// moving parallel to the plane, so it's either always or never touching
+		if signedDist >= 1.0 {
+			return false, 0, mgl.Vec3{}
+		}
+		embedded = true
+	} else {
+		t0 = (1.0 - signedDist) / nDotV
+		t1 := (-1.0 - signedDist) / nDotV
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		if t0 > 1.0 || t1 < 0.0 {
+			return false, 0, mgl.Vec3{}
+		}
+		t0 = max32(t0, 0.0)
+	}
+
+	// if the sphere touches the plane inside of the triangle, that's the
+	// first contact
+	if !embedded && t0 < maxT {
+		planePoint := base.Sub(normal).Add(vel.Mul(t0))
+		u, v, w := closestTriangleWeights(planePoint, p0, p1, p2)
+		if u > 0 && v > 0 && w > 0 {
+			return true, t0, planePoint
+		}
+	}
+
+	// otherwise sweep against the vertexes and edges
+	found := false
+	t := maxT
+	var point mgl.Vec3
+	velSq := vel.Dot(vel)
+
+	for _, p := range [3]mgl.Vec3{p0, p1, p2} {
+		b := 2.0 * vel.Dot(base.Sub(p))
+		c := p.Sub(base).Dot(p.Sub(base)) - 1.0
+		if root, ok := lowestRoot(velSq, b, c, t); ok {
+			t = root
+			found = true
+			point = p
+		}
+	}
+
+	edges := [3][2]mgl.Vec3{{p0, p1}, {p1, p2}, {p2, p0}}
+	for _, edge := range edges {
+		e := edge[1].Sub(edge[0])
+		baseToVertex := edge[0].Sub(base)
+		edgeSq := e.Dot(e)
+		edgeDotVel := e.Dot(vel)
+		edgeDotBase := e.Dot(baseToVertex)
+
+		a := edgeSq*-velSq + edgeDotVel*edgeDotVel
+		b := edgeSq*(2.0*vel.Dot(baseToVertex)) - 2.0*edgeDotVel*edgeDotBase
+		c := edgeSq*(1.0-baseToVertex.Dot(baseToVertex)) + edgeDotBase*edgeDotBase
+		if root, ok := lowestRoot(a, b, c, t); ok {
+			// make sure the contact is within the segment of the edge
+			f := (edgeDotVel*root - edgeDotBase) / edgeSq
+			if f >= 0.0 && f <= 1.0 {
+				t = root
+				found = true
+				point = edge[0].Add(e.Mul(f))
+			}
+		}
+	}
+
+	return found, t, point
+}
+
+// lowestRoot returns the lowest root of a*x*x + b*x + c between zero and maxR.
+func lowestRoot(a, b, c, maxR float32) (float32, bool) {
+	if a == 0 {
+		return 0, false
+	}
+	det := b*b - 4.0*a*c
+	if det < 0 {
+		return 0, false
+	}
+
+	sqrtD := float32(math.Sqrt(float64(det)))
+	r1 := (-b - sqrtD) / (2 * a)
+	r2 := (-b + sqrtD) / (2 * a)
+	if r1 > r2 {
+		r1, r2 = r2, r1
+	}
+	if r1 > 0 && r1 < maxR {
+		return r1, true
+	}
+	if r2 > 0 && r2 < maxR {
+		return r2, true
+	}
+	return 0, false
+}
@@ -0,0 +1,194 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestEllipsoid makes a tall ellipsoid like one that would fit a person.
+func newTestEllipsoid() *Ellipsoid {
+	e := NewEllipsoid()
+	e.Radii = mgl.Vec3{0.5, 1.0, 0.5}
+	return e
+}
+
+func TestEllipsoidCollisionVsShapes(t *testing.T) {
+	e := newTestEllipsoid()
+
+	// spheres above and beside the ellipsoid
+	sphere := Sphere{Center: mgl.Vec3{0.0, 1.4, 0.0}, Radius: 0.5}
+	if e.CollideVsSphere(&sphere) != Intersect {
+		t.Error("Ellipsoid.CollideVsSphere() indicated a sphere didn't collide that should have.")
+	}
+	sphere = Sphere{Center: mgl.Vec3{1.1, 0.0, 0.0}, Radius: 0.5}
+	if e.CollideVsSphere(&sphere) != NoIntersect {
+		t.Error("Ellipsoid.CollideVsSphere() indicated a sphere collided that should not have.")
+	}
+	if Collide(e, &sphere) != NoIntersect {
+		t.Error("Collide() indicated a sphere collided with an ellipsoid that should not have.")
+	}
+
+	// lay the ellipsoid down along the x axis
+	e.SetOrientation(mgl.QuatRotate(mgl.DegToRad(90.0), mgl.Vec3{0, 0, 1}))
+	if e.CollideVsSphere(&sphere) != Intersect {
+		t.Error("Ellipsoid.CollideVsSphere() indicated a sphere didn't collide with a rotated ellipsoid.")
+	}
+	e.SetOrientation(mgl.QuatIdent())
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-0.5, -0.5, -0.5}
+	b1.Max = mgl.Vec3{0.5, 0.5, 0.5}
+	b1.Offset = mgl.Vec3{0.0, 1.4, 0.0}
+	if e.CollideVsAABBox(&b1) != Intersect {
+		t.Error("Ellipsoid.CollideVsAABBox() indicated a box didn't collide that should have.")
+	}
+	b1.Offset = mgl.Vec3{1.1, 0.0, 0.0}
+	if e.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("Ellipsoid.CollideVsAABBox() indicated a box collided that should not have.")
+	}
+
+	// a box just beyond the ellipsoid's diagonal
+	b1.Offset = mgl.Vec3{0.8, 1.3, 0.0}
+	if e.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("Ellipsoid.CollideVsAABBox() indicated a box collided along the diagonal that should not have.")
+	}
+
+	obb := NewOBBox()
+	obb.HalfSize = mgl.Vec3{0.5, 0.5, 0.5}
+	obb.SetOffset(mgl.Vec3{1.15, 0.0, 0.0})
+	if e.CollideVsOBBox(obb) != NoIntersect {
+		t.Error("Ellipsoid.CollideVsOBBox() indicated a box collided that should not have.")
+	}
+	obb.SetOrientation(mgl.QuatRotate(mgl.DegToRad(45.0), mgl.Vec3{0, 0, 1}))
+	if e.CollideVsOBBox(obb) != Intersect {
+		t.Error("Ellipsoid.CollideVsOBBox() indicated a rotated box didn't collide that should have.")
+	}
+
+	p := NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 0.9, 0})
+	if e.CollideVsPlane(p) != Intersect {
+		t.Error("Ellipsoid.CollideVsPlane() indicated a plane didn't intersect that should have.")
+	}
+	p = NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 1.1, 0})
+	if e.CollideVsPlane(p) != NoIntersect {
+		t.Error("Ellipsoid.CollideVsPlane() indicated a plane intersected that should not have.")
+	}
+	p = NewPlaneFromNormalAndPoint(mgl.Vec3{1, 0, 0}, mgl.Vec3{0.6, 0, 0})
+	if e.CollideVsPlane(p) != NoIntersect {
+		t.Error("Ellipsoid.CollideVsPlane() indicated a plane intersected that should not have.")
+	}
+}
+
+func TestEllipsoidCollisionVsRay(t *testing.T) {
+	e := newTestEllipsoid()
+	e.SetOffset3f(10, 0, 0)
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+	intersect, dist := e.CollideVsRay(&r1)
+	if intersect != Intersect || !mgl.FloatEqualThreshold(dist, 9.5, 1e-4) {
+		t.Errorf("Ellipsoid.CollideVsRay() returned %d, %f instead of a hit at 9.5.", intersect, dist)
+	}
+
+	r1.Origin = mgl.Vec3{10.0, 10.0, 0.0}
+	r1.SetDirection(mgl.Vec3{0.0, -1.0, 0.0})
+	intersect, dist = e.CollideVsRay(&r1)
+	if intersect != Intersect || !mgl.FloatEqualThreshold(dist, 9.0, 1e-4) {
+		t.Errorf("Ellipsoid.CollideVsRay() returned %d, %f instead of a hit at 9.0.", intersect, dist)
+	}
+
+	r1.MaxDistance = 8.0
+	intersect, _ = e.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Ellipsoid.CollideVsRay() indicated a hit beyond the ray's MaxDistance.")
+	}
+	r1.MaxDistance = 0.0
+
+	// passes by the narrow side where a sphere of the tall radius would be hit
+	r1.Origin = mgl.Vec3{10.8, 10.0, 0.0}
+	intersect, _ = e.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Ellipsoid.CollideVsRay() indicated a ray intersected that should not have.")
+	}
+
+	// starting inside
+	r1.Origin = mgl.Vec3{10.0, 0.5, 0.0}
+	intersect, dist = e.CollideVsRay(&r1)
+	if intersect != Intersect || dist != 0.0 {
+		t.Errorf("Ellipsoid.CollideVsRay() returned %d, %f for a ray starting inside.", intersect, dist)
+	}
+}
+
+// newTestFloorAndWall makes a large floor at y=0 and a wall at x=5 facing -x.
+func newTestFloorAndWall() *Mesh {
+	verts := []mgl.Vec3{
+		{-10, 0, -10}, {10, 0, -10}, {10, 0, 10}, {-10, 0, 10},
+		{5, 0, -10}, {5, 10, -10}, {5, 10, 10}, {5, 0, 10},
+	}
+	indices := []uint32{0, 2, 1, 0, 3, 2, 4, 5, 6, 4, 6, 7}
+	return NewMesh(verts, indices)
+}
+
+func TestEllipsoidSweep(t *testing.T) {
+	world := newTestFloorAndWall()
+	e := newTestEllipsoid()
+	e.SetOffset3f(0, 3, 0)
+
+	// fall straight down onto the floor
+	intersect, hit := e.Sweep(mgl.Vec3{0, -4, 0}, world)
+	if intersect != Intersect {
+		t.Fatal("Ellipsoid.Sweep() didn't hit the floor.")
+	}
+	if !mgl.FloatEqualThreshold(hit.Time, 0.5, 1e-4) {
+		t.Errorf("Ellipsoid.Sweep() hit the floor at time %f instead of 0.5.", hit.Time)
+	}
+	if !hit.Point.ApproxEqualThreshold(mgl.Vec3{0, 0, 0}, 1e-4) {
+		t.Errorf("Ellipsoid.Sweep() hit the floor at %v.", hit.Point)
+	}
+	if !hit.Normal.ApproxEqualThreshold(mgl.Vec3{0, 1, 0}, 1e-4) {
+		t.Errorf("Ellipsoid.Sweep() returned the wrong normal for the floor: %v", hit.Normal)
+	}
+
+	// move sideways into the wall
+	intersect, hit = e.Sweep(mgl.Vec3{10, 0, 0}, world)
+	if intersect != Intersect || !mgl.FloatEqualThreshold(hit.Time, 0.45, 1e-4) {
+		t.Errorf("Ellipsoid.Sweep() hit the wall with %d at %f instead of 0.45.", intersect, hit.Time)
+	}
+	if !hit.Normal.ApproxEqualThreshold(mgl.Vec3{-1, 0, 0}, 1e-4) {
+		t.Errorf("Ellipsoid.Sweep() returned the wrong normal for the wall: %v", hit.Normal)
+	}
+
+	// a short move doesn't reach anything
+	intersect, _ = e.Sweep(mgl.Vec3{1, 0, 0}, world)
+	if intersect != NoIntersect {
+		t.Error("Ellipsoid.Sweep() hit something with a short move.")
+	}
+}
+
+func TestEllipsoidCollideAndSlide(t *testing.T) {
+	world := newTestFloorAndWall()
+	e := newTestEllipsoid()
+	e.SetOffset3f(0, 3, 0)
+
+	// falling ends up resting on the floor
+	offset := e.CollideAndSlide(mgl.Vec3{0, -4, 0}, world, 5)
+	if !offset.ApproxEqualThreshold(mgl.Vec3{0, 1, 0}, 0.01) {
+		t.Errorf("Ellipsoid.CollideAndSlide() fell to %v instead of resting on the floor.", offset)
+	}
+
+	// moving diagonally into the wall slides along it
+	offset = e.CollideAndSlide(mgl.Vec3{10, 0, 10}, world, 5)
+	if offset[0] > 4.5 || offset[0] < 4.4 {
+		t.Errorf("Ellipsoid.CollideAndSlide() went through the wall: %v", offset)
+	}
+	if !mgl.FloatEqualThreshold(offset[2], 10.0, 0.01) {
+		t.Errorf("Ellipsoid.CollideAndSlide() didn't slide along the wall: %v", offset)
+	}
+	if !mgl.FloatEqualThreshold(offset[1], 3.0, 0.01) {
+		t.Errorf("Ellipsoid.CollideAndSlide() moved vertically while sliding: %v", offset)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"errors"
+	"fmt"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var (
+	// ErrInvalidRay means a CollisionRay has a zero-length or non-finite
+	// direction, a non-finite origin or a negative MaxDistance.
+	ErrInvalidRay = errors.New("invalid collision ray")
+
+	// ErrNonFinite means a shape has a NaN or infinite value.
+	ErrNonFinite = errors.New("value is NaN or infinite")
+
+	// ErrInvertedBounds means a box has a Min corner greater than its Max corner.
+	ErrInvertedBounds = errors.New("min is greater than max")
+
+	// ErrNegativeRadius means a shape has a radius less than zero.
+	ErrNegativeRadius = errors.New("radius is negative")
+
+	// ErrZeroRadius means an ellipsoid has a radius of zero, which flattens
+	// it so that it can't be scaled into a unit sphere.
+	ErrZeroRadius = errors.New("radius is zero")
+
+	// ErrNegativeSize means a shape has a size less than zero.
+	ErrNegativeSize = errors.New("size is negative")
+
+	// ErrZeroNormal means a plane has a normal without any length.
+	ErrZeroNormal = errors.New("normal has zero length")
+
+	// ErrUnnormalizedOrientation means a shape has an orientation quaternion
+	// that isn't unit length.
+	ErrUnnormalizedOrientation = errors.New("is not a unit quaternion")
+
+	// ErrDegenerateTriangle means a triangle has no area.
+	ErrDegenerateTriangle = errors.New("triangle has no area")
+
+	// ErrInvalidIndex means a mesh has an index that doesn't refer to a
+	// vertex or doesn't have three indexes per triangle.
+	ErrInvalidIndex = errors.New("invalid vertex index")
+
+	// ErrInvalidDimensions means a grid shape has too few cells or doesn't
+	// have a value for every cell.
+	ErrInvalidDimensions = errors.New("invalid grid dimensions")
+
+	// ErrTooManyTags means a TagRegistry already holds MaxTags tags.
+	ErrTooManyTags = errors.New("too many tags registered")
+)
+
+const (
+	// orientationEpsilon is how far from unit length an orientation
+	// quaternion can be before it is considered invalid.
+	orientationEpsilon = 1e-3
+)
+
+// ShapeError is returned when a shape fails validation. The underlying
+// problem is one of the Err* values and can be tested with errors.Is.
+type ShapeError struct {
+	// Shape is the name of the type that failed validation (e.g. "AABBox").
+	Shape string
+
+	// Field is the name of the field that was invalid (e.g. "Radius").
+	Field string
+
+	// Err is the problem that was found.
+	Err error
+}
+
+// Error returns a description of the validation failure.
+func (e *ShapeError) Error() string {
+	return fmt.Sprintf("glider: invalid %s: %s %v", e.Shape, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying problem that was found.
+func (e *ShapeError) Unwrap() error {
+	return e.Err
+}
+
+// Validator is implemented by shapes that can check themselves for
+// degenerate values that would give wrong results in collision tests.
+type Validator interface {
+	Validate() error
+}
+
+// CollideStrict is like Collide but validates both colliders first and
+// returns the validation error instead of testing degenerate shapes.
+// Plane and OBBox aren't Colliders, so they have no strict path; call
+// their Validate functions before testing them instead.
+func CollideStrict(c1 Collider, c2 Collider) (int, error) {
+	if err := validateCollider(c1); err != nil {
+		return NoIntersect, err
+	}
+	if err := validateCollider(c2); err != nil {
+		return NoIntersect, err
+	}
+	return Collide(c1, c2), nil
+}
+
+// CollideVsRayStrict validates the collider and the ray before testing them
+// and returns the validation error instead of testing degenerate input.
+func CollideVsRayStrict(c Collider, ray *CollisionRay) (int, float32, error) {
+	if err := ray.Validate(); err != nil {
+		return NoIntersect, 0.0, err
+	}
+	if err := validateCollider(c); err != nil {
+		return NoIntersect, 0.0, err
+	}
+	result, dist := c.CollideVsRay(ray)
+	return result, dist, nil
+}
+
+// validateCollider validates the collider if it's a Validator.
+func validateCollider(c Collider) error {
+	v, okay := c.(Validator)
+	if !okay {
+		return nil
+	}
+	return v.Validate()
+}
+
+// validateFinite returns a ShapeError if any component of v is NaN or infinite.
+func validateFinite(shape, field string, v []float32) error {
+	for _, f := range v {
+		if !isFinite32(f) {
+			return &ShapeError{Shape: shape, Field: field, Err: ErrNonFinite}
+		}
+	}
+	return nil
+}
+
+// validateBounds returns a ShapeError if any component of min is
+// greater than max.
+func validateBounds(shape string, min, max []float32) error {
+	for i := range min {
+		if min[i] > max[i] {
+			return &ShapeError{Shape: shape, Field: "Min", Err: ErrInvertedBounds}
+		}
+	}
+	return nil
+}
+
+// validateOrientation returns a ShapeError if q isn't unit length.
+func validateOrientation(shape string, q mgl.Quat) error {
+	if fabs32(q.Len()-1.0) > orientationEpsilon {
+		return &ShapeError{Shape: shape, Field: "orientation", Err: ErrUnnormalizedOrientation}
+	}
+	return nil
+}
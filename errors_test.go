@@ -0,0 +1,119 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestShapeValidate(t *testing.T) {
+	nan := float32(math.NaN())
+
+	tests := []struct {
+		name     string
+		shape    Validator
+		expected error
+	}{
+		{"valid box", &AABBox{Min: mgl.Vec3{-1, -1, -1}, Max: mgl.Vec3{1, 1, 1}}, nil},
+		{"inverted box", &AABBox{Min: mgl.Vec3{1, -1, -1}, Max: mgl.Vec3{-1, 1, 1}}, ErrInvertedBounds},
+		{"NaN box", &AABBox{Min: mgl.Vec3{nan, -1, -1}, Max: mgl.Vec3{1, 1, 1}}, ErrNonFinite},
+		{"valid square", &AABSquare{Min: mgl.Vec2{0, 0}, Max: mgl.Vec2{1, 1}}, nil},
+		{"inverted square", &AABSquare{Min: mgl.Vec2{0, 2}, Max: mgl.Vec2{1, 1}}, ErrInvertedBounds},
+		{"valid sphere", &Sphere{Radius: 1}, nil},
+		{"negative sphere", &Sphere{Radius: -1}, ErrNegativeRadius},
+		{"NaN sphere", &Sphere{Radius: nan}, ErrNonFinite},
+		{"valid plane", NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{}), nil},
+		{"zero normal plane", &Plane{}, ErrZeroNormal},
+		{"valid obb", NewOBBox(), nil},
+		{"zero value obb", &OBBox{}, nil},
+		{"unnormalized obb", &OBBox{orientation: mgl.Quat{W: 2}}, ErrUnnormalizedOrientation},
+		{"valid triangle", NewTriangle(mgl.Vec3{0, 0, 0}, mgl.Vec3{1, 0, 0}, mgl.Vec3{0, 1, 0}), nil},
+		{"degenerate triangle", NewTriangle(mgl.Vec3{0, 0, 0}, mgl.Vec3{1, 0, 0}, mgl.Vec3{2, 0, 0}), ErrDegenerateTriangle},
+		{"valid ellipsoid", newTestEllipsoid(), nil},
+		{"negative ellipsoid", &Ellipsoid{Radii: mgl.Vec3{1, -1, 1}}, ErrNegativeRadius},
+		{"flat ellipsoid", &Ellipsoid{Radii: mgl.Vec3{1, 1, 0}}, ErrZeroRadius},
+		{"valid cylinder", &Cylinder{Radius: 1, HalfHeight: 1}, nil},
+		{"negative cylinder", &Cylinder{Radius: 1, HalfHeight: -1}, ErrNegativeSize},
+		{"valid cone", NewConeFromDirection(mgl.Vec3{}, mgl.Vec3{0, 0, -1}, 10, 0.5), nil},
+		{"negative cone", &Cone{Radius: -1, Height: 1}, ErrNegativeRadius},
+		{"valid heightfield", newTestRampHeightfield(), nil},
+		{"short heightfield", NewHeightfield(4, 4, make([]float32, 15), 1.0), ErrInvalidDimensions},
+		{"valid voxel grid", newTestVoxelFloor(), nil},
+		{"empty voxel grid", &VoxelGrid{Width: 1, Height: 1, Depth: 1, CellSize: 1}, ErrInvalidDimensions},
+		{"valid tile map", newTestTileLevel(), nil},
+		{"short tile map", &TileMap{Width: 2, Height: 2, TileSize: 1, Tiles: make([]TileKind, 3)}, ErrInvalidDimensions},
+		{"valid mesh", newTestQuadMesh(), nil},
+		{"bad index mesh", NewMesh([]mgl.Vec3{{0, 0, 0}}, []uint32{0, 0, 1}), ErrInvalidIndex},
+		{"short index mesh", NewMesh([]mgl.Vec3{{0, 0, 0}}, []uint32{0, 0}), ErrInvalidIndex},
+	}
+
+	for _, test := range tests {
+		err := test.shape.Validate()
+		if test.expected == nil {
+			if err != nil {
+				t.Errorf("%s: Validate() returned an error for a valid shape: %v", test.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%s: Validate() returned %v instead of %v", test.name, err, test.expected)
+		}
+		var shapeErr *ShapeError
+		if !errors.As(err, &shapeErr) {
+			t.Errorf("%s: Validate() didn't return a *ShapeError", test.name)
+		}
+	}
+
+	// a rotated box with an unnormalized quaternion
+	obb := NewOBBox()
+	obb.SetOrientation(mgl.Quat{W: 2.0})
+	err := obb.Validate()
+	if !errors.Is(err, ErrUnnormalizedOrientation) {
+		t.Error("OBBox.Validate() didn't report an unnormalized orientation.")
+	}
+	if msg := err.Error(); msg != "glider: invalid OBBox: orientation is not a unit quaternion" {
+		t.Errorf("OBBox.Validate() returned the message %q", msg)
+	}
+}
+
+func TestCollideStrict(t *testing.T) {
+	b1 := &AABBox{Min: mgl.Vec3{-1, -1, -1}, Max: mgl.Vec3{1, 1, 1}}
+	s1 := &Sphere{Radius: 1}
+
+	result, err := CollideStrict(b1, s1)
+	if err != nil || result != Intersect {
+		t.Errorf("CollideStrict() didn't collide valid shapes: %d, %v", result, err)
+	}
+
+	s1.Radius = -1
+	_, err = CollideStrict(b1, s1)
+	if !errors.Is(err, ErrNegativeRadius) {
+		t.Errorf("CollideStrict() didn't report a negative radius: %v", err)
+	}
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{5, 0, 0}
+	r1.SetDirection(mgl.Vec3{-1, 0, 0})
+	result, dist, err := CollideVsRayStrict(b1, &r1)
+	if err != nil || result != Intersect || dist != 4.0 {
+		t.Errorf("CollideVsRayStrict() didn't hit a valid box: %d, %f, %v", result, dist, err)
+	}
+
+	r1.SetDirection(mgl.Vec3{0, 0, 0})
+	_, _, err = CollideVsRayStrict(b1, &r1)
+	if !errors.Is(err, ErrInvalidRay) {
+		t.Errorf("CollideVsRayStrict() didn't report an invalid ray: %v", err)
+	}
+
+	r1.SetDirection(mgl.Vec3{-1, 0, 0})
+	b1.Max[0] = -2
+	_, _, err = CollideVsRayStrict(b1, &r1)
+	if !errors.Is(err, ErrInvertedBounds) {
+		t.Errorf("CollideVsRayStrict() didn't report an inverted box: %v", err)
+	}
+}
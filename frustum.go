@@ -0,0 +1,84 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// FrustumLeft is the index of the left plane in Frustum.Planes.
+	FrustumLeft = iota
+
+	// FrustumRight is the index of the right plane in Frustum.Planes.
+	FrustumRight
+
+	// FrustumBottom is the index of the bottom plane in Frustum.Planes.
+	FrustumBottom
+
+	// FrustumTop is the index of the top plane in Frustum.Planes.
+	FrustumTop
+
+	// FrustumNear is the index of the near plane in Frustum.Planes.
+	FrustumNear
+
+	// FrustumFar is the index of the far plane in Frustum.Planes.
+	FrustumFar
+)
+
+// Frustum is a convex volume bounded by six planes whose normals all point
+// inwards, such as the viewing volume of a camera.
+type Frustum struct {
+	// Planes holds the bounding planes indexed by the Frustum* constants.
+	Planes [6]Plane
+}
+
+// NewFrustumFromMatrix extracts the frustum planes from a combined
+// projection * view matrix. If only a projection matrix is passed, the
+// frustum will be in view space.
+func NewFrustumFromMatrix(m mgl.Mat4) *Frustum {
+	// implementation based on Gribb & Hartmann's "Fast Extraction of Viewing
+	// Frustum Planes from the World-View-Projection Matrix"
+	f := new(Frustum)
+	r0 := m.Row(0)
+	r1 := m.Row(1)
+	r2 := m.Row(2)
+	r3 := m.Row(3)
+
+	f.Planes[FrustumLeft] = planeFromVec4(r3.Add(r0))
+	f.Planes[FrustumRight] = planeFromVec4(r3.Sub(r0))
+	f.Planes[FrustumBottom] = planeFromVec4(r3.Add(r1))
+	f.Planes[FrustumTop] = planeFromVec4(r3.Sub(r1))
+	f.Planes[FrustumNear] = planeFromVec4(r3.Add(r2))
+	f.Planes[FrustumFar] = planeFromVec4(r3.Sub(r2))
+	return f
+}
+
+// planeFromVec4 makes a normalized plane from the plane equation coefficients.
+func planeFromVec4(v mgl.Vec4) Plane {
+	var p Plane
+	n := mgl.Vec3{v[0], v[1], v[2]}
+	l := n.Len()
+	if l == 0 {
+		return p
+	}
+	p.Normal = n.Mul(1.0 / l)
+	p.D = v[3] / l
+	return p
+}
+
+// classify combines the classification of a shape against each of the
+// frustum's planes using classifyPlane.
+func (f *Frustum) classify(classifyPlane func(p *Plane) Classification) Classification {
+	result := Inside
+	for i := range f.Planes {
+		switch classifyPlane(&f.Planes[i]) {
+		case Back:
+			return Outside
+		case Straddling:
+			result = Intersecting
+		}
+	}
+	return result
+}
@@ -0,0 +1,342 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// gjkMaxIterations limits how many times the GJK loop will refine its
+	// simplex before giving up and returning the best answer so far.
+	gjkMaxIterations = 64
+
+	// gjkTolerance is the relative amount the distance estimate has to
+	// improve by for the GJK loop to keep going.
+	gjkTolerance = 1e-5
+
+	// gjkContactTolerance is how close two shapes can be before they
+	// are considered to be touching.
+	gjkContactTolerance = 1e-4
+)
+
+// convexShape is implemented by convex shapes that can return a support
+// point: the point on the shape that is furthest along a direction.
+// Both the direction and the point are in world space.
+type convexShape interface {
+	support(d mgl.Vec3) mgl.Vec3
+}
+
+// pointShape is a single point in space usable as a convexShape.
+type pointShape mgl.Vec3
+
+func (p pointShape) support(d mgl.Vec3) mgl.Vec3 {
+	return mgl.Vec3(p)
+}
+
+// simplexVertex is a point on the Minkowski difference of two shapes along
+// with the support points on each shape that made it.
+type simplexVertex struct {
+	a, b, w mgl.Vec3
+}
+
+// simplex is the set of up to four vertexes that GJK refines, along with
+// the barycentric weights of the point on it closest to the origin.
+type simplex struct {
+	verts  [4]simplexVertex
+	lambda [4]float32
+	count  int
+}
+
+// newSimplexVertex builds a vertex on the Minkowski difference s1 - s2 that is
+// furthest along d.
+func newSimplexVertex(s1, s2 convexShape, d mgl.Vec3) simplexVertex {
+	var sv simplexVertex
+	sv.a = s1.support(d)
+	sv.b = s2.support(d.Mul(-1.0))
+	sv.w = sv.a.Sub(sv.b)
+	return sv
+}
+
+// gjkDistance returns the distance between two convex shapes and the closest
+// points on each of them using the Gilbert–Johnson–Keerthi algorithm. If the
+// shapes overlap the distance is zero and the points are only approximate.
+func gjkDistance(s1, s2 convexShape) (float32, mgl.Vec3, mgl.Vec3) {
+	var s simplex
+	s.verts[0] = newSimplexVertex(s1, s2, mgl.Vec3{1, 0, 0})
+	s.lambda[0] = 1
+	s.count = 1
+	v := s.verts[0].w
+
+	for i := 0; i < gjkMaxIterations; i++ {
+		vv := v.Dot(v)
+		if vv <= gjkContactTolerance*gjkContactTolerance {
+			break
+		}
+
+		// find the support point in the direction of the origin and stop
+		// if it doesn't get us any closer
+		w := newSimplexVertex(s1, s2, v.Mul(-1.0))
+		if vv-v.Dot(w.w) <= gjkTolerance*vv || s.contains(w.w) {
+			break
+		}
+
+		prev := s
+		s.verts[s.count] = w
+		s.count++
+		newV := s.closest()
+
+		// the origin is enclosed by the tetrahedron so the shapes overlap
+		if s.count == 4 {
+			p1, p2 := s.witnesses()
+			return 0.0, p1, p2
+		}
+
+		// numerical trouble can cause the estimate to stop improving
+		if newV.Dot(newV) >= vv {
+			s = prev
+			break
+		}
+		v = newV
+	}
+
+	p1, p2 := s.witnesses()
+	dist := float32(math.Sqrt(float64(v.Dot(v))))
+	if dist <= gjkContactTolerance {
+		dist = 0.0
+	}
+	return dist, p1, p2
+}
+
+// gjkIntersect returns true if the two convex shapes are touching.
+func gjkIntersect(s1, s2 convexShape) bool {
+	dist, _, _ := gjkDistance(s1, s2)
+	return dist <= gjkContactTolerance
+}
+
+// convexVsPlane tests a convex shape against a plane. Like the other shapes,
+// the convex shape only fails to intersect if it lies completely behind the
+// plane, which happens when the point furthest along the normal is behind it.
+func convexVsPlane(s convexShape, p *Plane) int {
+	if p.Distance(s.support(p.Normal)) < 0 {
+		return NoIntersect
+	}
+	return Intersect
+}
+
+// convexVsTriangles tests a convex shape against each of the triangles that
+// overlap its bounds. Heightfields only test the triangles in the cells
+// under the shape.
+func convexVsTriangles(s convexShape, b Bounds, triangles TriangleSet) int {
+	var tri triangleShape
+	if hf, ok := triangles.(*Heightfield); ok {
+		return hf.collideVsTriangles(b.Min, b.Max, func(v0, v1, v2 mgl.Vec3) bool {
+			tri = triangleShape{v0, v1, v2}
+			return gjkIntersect(s, &tri)
+		})
+	}
+
+	triCount := triangles.TriangleCount()
+	for i := 0; i < triCount; i++ {
+		v0, v1, v2 := triangles.Triangle(i)
+		if !b.Overlaps(boundsFromPoints(v0, v1, v2)) {
+			continue
+		}
+		tri = triangleShape{v0, v1, v2}
+		if gjkIntersect(s, &tri) {
+			return Intersect
+		}
+	}
+	return NoIntersect
+}
+
+// convexVsVoxels tests a convex shape against each of the solid voxels that
+// overlap its bounds.
+func convexVsVoxels(s convexShape, b Bounds, vg *VoxelGrid) int {
+	lo, hi, ok := vg.voxelRange(b.Min, b.Max)
+	if !ok {
+		return NoIntersect
+	}
+	var voxel AABBox
+	for z := lo[2]; z <= hi[2]; z++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for x := lo[0]; x <= hi[0]; x++ {
+				if !vg.IsSolid(x, y, z) {
+					continue
+				}
+				voxel.Min, voxel.Max = vg.VoxelBounds(x, y, z)
+				if gjkIntersect(s, &voxel) {
+					return Intersect
+				}
+			}
+		}
+	}
+	return NoIntersect
+}
+
+// convexVsCollider tests a convex shape against a collider that has no
+// support function, such as a mesh or a voxel grid.
+func convexVsCollider(s convexShape, b Bounds, c Collider) int {
+	switch target := c.(type) {
+	case *Triangle:
+		return convexVsTriangles(s, b, singleTriangle{target})
+	case *VoxelGrid:
+		return convexVsVoxels(s, b, target)
+	case TriangleSet:
+		return convexVsTriangles(s, b, target)
+	}
+	return NoIntersect
+}
+
+// collidePair tests any two colliders against each other. Boxes and spheres
+// use the tests every collider implements, other convex shapes use GJK and
+// meshes, heightfields and voxel grids are tested a piece at a time against
+// the convex shape. Pairs without a convex side, such as two meshes, never
+// intersect.
+func collidePair(c1, c2 Collider) int {
+	switch s := c2.(type) {
+	case *AABBox:
+		return c1.CollideVsAABBox(s)
+	case *Sphere:
+		return c1.CollideVsSphere(s)
+	}
+	switch s := c1.(type) {
+	case *AABBox:
+		return c2.CollideVsAABBox(s)
+	case *Sphere:
+		return c2.CollideVsSphere(s)
+	}
+
+	s1, convex1 := c1.(convexShape)
+	s2, convex2 := c2.(convexShape)
+	switch {
+	case convex1 && convex2:
+		if gjkIntersect(s1, s2) {
+			return Intersect
+		}
+		return NoIntersect
+	case convex1:
+		return convexVsCollider(s1, ColliderBounds(c1), c2)
+	case convex2:
+		return convexVsCollider(s2, ColliderBounds(c2), c1)
+	}
+	return NoIntersect
+}
+
+// contains returns true if w is already a vertex of the simplex.
+func (s *simplex) contains(w mgl.Vec3) bool {
+	for i := 0; i < s.count; i++ {
+		if s.verts[i].w == w {
+			return true
+		}
+	}
+	return false
+}
+
+// witnesses returns the closest points on the two shapes using the weights
+// of the simplex vertexes.
+func (s *simplex) witnesses() (mgl.Vec3, mgl.Vec3) {
+	var p1, p2 mgl.Vec3
+	for i := 0; i < s.count; i++ {
+		p1 = p1.Add(s.verts[i].a.Mul(s.lambda[i]))
+		p2 = p2.Add(s.verts[i].b.Mul(s.lambda[i]))
+	}
+	return p1, p2
+}
+
+// closest finds the point on the simplex closest to the origin, reduces the
+// simplex to the smallest set of vertexes that support it and returns it.
+// The simplex is only left with four vertexes if the origin is inside it.
+func (s *simplex) closest() mgl.Vec3 {
+	switch s.count {
+	case 2:
+		s.closestSegment()
+	case 3:
+		s.closestTriangle()
+	case 4:
+		s.closestTetrahedron()
+	default:
+		s.lambda[0] = 1
+	}
+
+	// drop the vertexes that don't contribute to the closest point
+	n := 0
+	var v mgl.Vec3
+	for i := 0; i < s.count; i++ {
+		if s.lambda[i] <= 0 {
+			continue
+		}
+		s.verts[n] = s.verts[i]
+		s.lambda[n] = s.lambda[i]
+		v = v.Add(s.verts[n].w.Mul(s.lambda[n]))
+		n++
+	}
+	s.count = n
+	return v
+}
+
+func (s *simplex) closestSegment() {
+	a := s.verts[0].w
+	ab := s.verts[1].w.Sub(a)
+	denom := ab.Dot(ab)
+	t := float32(0.0)
+	if denom > 0 {
+		t = -a.Dot(ab) / denom
+	}
+	if t <= 0 {
+		s.lambda[0], s.lambda[1] = 1, 0
+	} else if t >= 1 {
+		s.lambda[0], s.lambda[1] = 0, 1
+	} else {
+		s.lambda[0], s.lambda[1] = 1-t, t
+	}
+}
+
+func (s *simplex) closestTriangle() {
+	var origin mgl.Vec3
+	s.lambda[0], s.lambda[1], s.lambda[2] = closestTriangleWeights(origin, s.verts[0].w, s.verts[1].w, s.verts[2].w)
+}
+
+func (s *simplex) closestTetrahedron() {
+	faces := [4][4]int{{0, 1, 2, 3}, {0, 1, 3, 2}, {0, 2, 3, 1}, {1, 2, 3, 0}}
+
+	var origin mgl.Vec3
+	bestDist := float32(math.Inf(1))
+	var best [4]float32
+	outside := false
+	for _, f := range faces {
+		a := s.verts[f[0]].w
+		b := s.verts[f[1]].w
+		c := s.verts[f[2]].w
+		d := s.verts[f[3]].w
+
+		// only faces with the origin on the opposite side from the
+		// fourth vertex can hold the closest point
+		n := b.Sub(a).Cross(c.Sub(a))
+		signOrigin := -n.Dot(a)
+		signOpposite := n.Dot(d.Sub(a))
+		if signOrigin*signOpposite > 0 {
+			continue
+		}
+		outside = true
+
+		u, v, w := closestTriangleWeights(origin, a, b, c)
+		p := a.Mul(u).Add(b.Mul(v)).Add(c.Mul(w))
+		if dist := p.Dot(p); dist < bestDist {
+			bestDist = dist
+			best = [4]float32{}
+			best[f[0]], best[f[1]], best[f[2]] = u, v, w
+		}
+	}
+
+	if !outside {
+		// the origin is inside the tetrahedron; leave all four vertexes
+		// with equal weight so the witness points are still reasonable.
+		s.lambda = [4]float32{0.25, 0.25, 0.25, 0.25}
+		return
+	}
+	s.lambda = best
+}
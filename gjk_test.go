@@ -0,0 +1,84 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestGJKDistance(t *testing.T) {
+	s1 := &Sphere{Radius: 1.0}
+	s2 := &Sphere{Radius: 1.0, Offset: mgl.Vec3{5.0, 0.0, 0.0}}
+
+	dist, p1, p2 := gjkDistance(s1, s2)
+	if !mgl.FloatEqualThreshold(dist, 3.0, 1e-3) {
+		t.Errorf("gjkDistance() returned %f between spheres instead of 3.", dist)
+	}
+	if !p1.ApproxEqualThreshold(mgl.Vec3{1, 0, 0}, 1e-3) || !p2.ApproxEqualThreshold(mgl.Vec3{4, 0, 0}, 1e-3) {
+		t.Errorf("gjkDistance() returned the wrong witness points: %v, %v", p1, p2)
+	}
+
+	// boxes offset diagonally are closest at their corners
+	b1 := &AABBox{Min: mgl.Vec3{-1, -1, -1}, Max: mgl.Vec3{1, 1, 1}}
+	b2 := &AABBox{Min: mgl.Vec3{-1, -1, -1}, Max: mgl.Vec3{1, 1, 1}, Offset: mgl.Vec3{4, 4, 4}}
+	dist, _, _ = gjkDistance(b1, b2)
+	if !mgl.FloatEqualThreshold(dist, 3.4641016, 1e-3) {
+		t.Errorf("gjkDistance() returned %f between boxes instead of 3.464.", dist)
+	}
+
+	// overlapping shapes
+	b2.Offset = mgl.Vec3{1.5, 0.5, 0.0}
+	dist, _, _ = gjkDistance(b1, b2)
+	if dist != 0.0 {
+		t.Errorf("gjkDistance() returned %f between overlapping boxes.", dist)
+	}
+	if !gjkIntersect(b1, s1) {
+		t.Error("gjkIntersect() didn't report a sphere inside a box as intersecting.")
+	}
+
+	// a point vs a rotated box
+	obb := NewOBBox()
+	obb.HalfSize = mgl.Vec3{1, 1, 1}
+	obb.SetOrientation(mgl.QuatRotate(mgl.DegToRad(45.0), mgl.Vec3{0, 0, 1}))
+	dist, _, _ = gjkDistance(obb, pointShape{3, 0, 0})
+	if !mgl.FloatEqualThreshold(dist, 3.0-1.4142135, 1e-3) {
+		t.Errorf("gjkDistance() returned %f between a point and a rotated box.", dist)
+	}
+}
+
+func TestCollidePairConvexShapes(t *testing.T) {
+	c := newTestSpotCone()
+	e := NewEllipsoid()
+	e.Radii = mgl.Vec3{1, 2, 1}
+	e.SetOffset3f(0.0, 0.0, -5.0)
+	if collidePair(c, e) != Intersect || collidePair(e, c) != Intersect {
+		t.Error("collidePair() didn't intersect an ellipsoid inside a cone.")
+	}
+	e.SetOffset3f(0.0, 8.0, -5.0)
+	if collidePair(c, e) != NoIntersect || collidePair(e, c) != NoIntersect {
+		t.Error("collidePair() intersected an ellipsoid above a cone.")
+	}
+
+	vg := newTestVoxelFloor()
+	cyl := NewCylinder()
+	cyl.Radius = 0.4
+	cyl.HalfHeight = 0.5
+	cyl.SetOffset3f(2.5, 1.4, 2.5)
+	if collidePair(cyl, vg) != Intersect || collidePair(vg, cyl) != Intersect {
+		t.Error("collidePair() didn't intersect a cylinder sunk into a voxel floor.")
+	}
+	cyl.SetOffset3f(2.5, 1.6, 2.5)
+	if collidePair(cyl, vg) != NoIntersect || collidePair(vg, cyl) != NoIntersect {
+		t.Error("collidePair() intersected a cylinder resting above a voxel floor.")
+	}
+
+	m := newTestQuadMesh()
+	m.SetOffset3f(2.5, 1.0, 2.5)
+	cyl.SetOffset3f(2.5, 1.4, 2.5)
+	if collidePair(cyl, m) != Intersect || collidePair(m, cyl) != Intersect {
+		t.Error("collidePair() didn't intersect a cylinder through a mesh.")
+	}
+}
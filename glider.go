@@ -22,6 +22,12 @@ const (
 	Intersect = 1
 )
 
+const (
+	// rayEpsilon is the tolerance used to reject rays that run parallel
+	// to a plane or triangle.
+	rayEpsilon = 1e-7
+)
+
 // Collider is an interface for objects that con collide with other
 // collision primitives.
 type Collider interface {
@@ -34,8 +40,8 @@ type Collider interface {
 }
 
 // Collide tests two objects that are Colliders and returns the collision test result.
-// NOTE: currently this supports cubes and spheres.
-// FIXME: planes and rays are not tested here
+// NOTE: currently this only supports c2 being a cube or a sphere; any other
+// c2 returns NoIntersect. Rays are tested with CollideVsRay instead.
 func Collide(c1 Collider, c2 Collider) int {
 	targetBox, okay := c2.(*AABBox)
 	if okay {
@@ -55,6 +61,10 @@ type CollisionRay struct {
 	// Origin is the start of the ray
 	Origin mgl.Vec3
 
+	// MaxDistance is the furthest distance along the ray that a hit will be
+	// reported at. A value of zero means the ray is unbounded.
+	MaxDistance float32
+
 	// direction is the unit vector representing the direction of the ray
 	direction mgl.Vec3
 
@@ -62,20 +72,64 @@ type CollisionRay struct {
 	directionFraction mgl.Vec3
 }
 
+// NewCollisionRay creates a new CollisionRay starting at origin and pointing
+// in direction. An error is returned if the resulting ray is not valid.
+func NewCollisionRay(origin, direction mgl.Vec3) (*CollisionRay, error) {
+	cr := new(CollisionRay)
+	cr.Origin = origin
+	cr.SetDirection(direction)
+	if err := cr.Validate(); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
 // SetDirection sets the direction of the collision ray. Will be normalized
-// and have some math cached as well.
+// and have some math cached as well. A zero-length or non-finite direction
+// leaves the ray without a direction so that it never hits anything;
+// Validate can be used to detect this.
 func (cr *CollisionRay) SetDirection(d mgl.Vec3) {
 	// normalize the direction vector
 	dLen := float32(math.Sqrt(float64(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])))
+	if dLen == 0 || !isFinite32(dLen) {
+		cr.direction = mgl.Vec3{}
+		cr.directionFraction = mgl.Vec3{}
+		return
+	}
+
 	l := 1.0 / dLen
-	cr.direction[0] = d[0] * l
-	cr.direction[1] = d[1] * l
-	cr.direction[2] = d[2] * l
-
-	// cache some math calculations
-	cr.directionFraction[0] = 1.0 / cr.direction[0]
-	cr.directionFraction[1] = 1.0 / cr.direction[1]
-	cr.directionFraction[2] = 1.0 / cr.direction[2]
+	for i := 0; i < 3; i++ {
+		cr.direction[i] = d[i] * l
+
+		// cache some math calculations; components so small that their
+		// reciprocal overflows are treated as parallel to that axis.
+		cr.directionFraction[i] = 1.0 / cr.direction[i]
+		if !isFinite32(cr.directionFraction[i]) {
+			cr.direction[i] = 0
+			cr.directionFraction[i] = 0
+		}
+	}
+}
+
+// Validate returns a ShapeError wrapping ErrInvalidRay if the ray has no
+// usable direction, has a non-finite origin or has a negative or non-finite
+// MaxDistance.
+func (cr *CollisionRay) Validate() error {
+	if !cr.valid() {
+		return &ShapeError{Shape: "CollisionRay", Field: "direction", Err: ErrInvalidRay}
+	}
+	if validateFinite("CollisionRay", "Origin", cr.Origin[:]) != nil {
+		return &ShapeError{Shape: "CollisionRay", Field: "Origin", Err: ErrInvalidRay}
+	}
+	if cr.MaxDistance < 0 || !isFinite32(cr.MaxDistance) {
+		return &ShapeError{Shape: "CollisionRay", Field: "MaxDistance", Err: ErrInvalidRay}
+	}
+	return nil
+}
+
+// valid returns true if the ray has a direction to cast in.
+func (cr *CollisionRay) valid() bool {
+	return cr.direction[0] != 0 || cr.direction[1] != 0 || cr.direction[2] != 0
 }
 
 // GetDirection gets the direction of the collision ray.
@@ -83,6 +137,34 @@ func (cr *CollisionRay) GetDirection() mgl.Vec3 {
 	return cr.direction
 }
 
+// SetSegment turns the collision ray into a line segment running from start
+// to end so that hits beyond end are not reported.
+func (cr *CollisionRay) SetSegment(start, end mgl.Vec3) {
+	delta := end.Sub(start)
+	cr.Origin = start
+	cr.SetDirection(delta)
+	cr.MaxDistance = delta.Len()
+}
+
+// inRange returns true if the distance t along the ray is within the
+// ray's MaxDistance.
+func (cr *CollisionRay) inRange(t float32) bool {
+	return cr.MaxDistance <= 0 || t <= cr.MaxDistance
+}
+
+// orientationOrIdent treats the zero value of a quaternion as no rotation so
+// that shapes made without their New function still work.
+func orientationOrIdent(q mgl.Quat) mgl.Quat {
+	if q.W == 0 && q.V == (mgl.Vec3{}) {
+		return mgl.QuatIdent()
+	}
+	return q
+}
+
+func isFinite32(x float32) bool {
+	return !math.IsNaN(float64(x)) && !math.IsInf(float64(x), 0)
+}
+
 func max32(x, y float32) float32 {
 	switch {
 	case math.IsInf(float64(x), 1) || math.IsInf(float64(y), 1):
@@ -0,0 +1,421 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// Heightfield is a collision shape for terrain made from a grid of height
+// samples. The samples are laid out along the X and Z axes Spacing units
+// apart starting at Offset and every grid cell is split into two triangles.
+type Heightfield struct {
+	// Heights holds Width * Depth height samples in rows along the X axis,
+	// so the sample at (x, z) is Heights[z*Width+x].
+	Heights []float32
+
+	// Width is the number of samples along the X axis.
+	Width int
+
+	// Depth is the number of samples along the Z axis.
+	Depth int
+
+	// Spacing is the distance between samples on the X and Z axes.
+	Spacing float32
+
+	// Offset is the world-space location of the first height sample
+	Offset mgl.Vec3
+
+	// Tags provides a way to label a heightfield in a custom application
+	// (e.g. labelling a collision as "terrain" or "ground").
+	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// heightfield is hit, such as the terrain patch it was built from.
+	UserData interface{}
+
+	// Material describes the surface of the heightfield.
+	Material Material
+}
+
+// NewHeightfield creates a new Heightfield object from width * depth height
+// samples spaced spacing units apart.
+func NewHeightfield(width, depth int, heights []float32, spacing float32) *Heightfield {
+	hf := new(Heightfield)
+	hf.Width = width
+	hf.Depth = depth
+	hf.Heights = heights
+	hf.Spacing = spacing
+	return hf
+}
+
+// NewHeightfieldFromImage creates a new Heightfield object with a height
+// sample for every pixel of the image. The pixels are converted to grayscale
+// and scaled so that black has a height of zero and white has a height
+// of heightScale.
+func NewHeightfieldFromImage(img image.Image, spacing, heightScale float32) *Heightfield {
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	depth := bounds.Dy()
+
+	heights := make([]float32, width*depth)
+	for z := 0; z < depth; z++ {
+		for x := 0; x < width; x++ {
+			gray := color.Gray16Model.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+z)).(color.Gray16)
+			heights[z*width+x] = float32(gray.Y) / math.MaxUint16 * heightScale
+		}
+	}
+
+	return NewHeightfield(width, depth, heights, spacing)
+}
+
+// SetOffset changes the offset of the collision object.
+func (hf *Heightfield) SetOffset(offset *mgl.Vec3) {
+	hf.Offset = *offset
+}
+
+// SetOffset3f changes the offset of the collision object.
+func (hf *Heightfield) SetOffset3f(x, y, z float32) {
+	hf.Offset[0] = x
+	hf.Offset[1] = y
+	hf.Offset[2] = z
+}
+
+// Validate checks the Heightfield for non-finite values, a grid without any
+// cells, a height count that doesn't match the grid and a spacing that
+// isn't positive.
+func (hf *Heightfield) Validate() error {
+	if hf.Width < 2 || hf.Depth < 2 || len(hf.Heights) != hf.Width*hf.Depth {
+		return &ShapeError{Shape: "Heightfield", Field: "Heights", Err: ErrInvalidDimensions}
+	}
+	if err := validateFinite("Heightfield", "Heights", hf.Heights); err != nil {
+		return err
+	}
+	if err := validateFinite("Heightfield", "Offset", hf.Offset[:]); err != nil {
+		return err
+	}
+	if !isFinite32(hf.Spacing) {
+		return &ShapeError{Shape: "Heightfield", Field: "Spacing", Err: ErrNonFinite}
+	}
+	if hf.Spacing <= 0 {
+		return &ShapeError{Shape: "Heightfield", Field: "Spacing", Err: ErrNegativeSize}
+	}
+	return nil
+}
+
+// cellsX returns the number of grid cells along the X axis.
+func (hf *Heightfield) cellsX() int {
+	return hf.Width - 1
+}
+
+// cellsZ returns the number of grid cells along the Z axis.
+func (hf *Heightfield) cellsZ() int {
+	return hf.Depth - 1
+}
+
+// vertex returns the world-space position of the height sample at (x, z).
+func (hf *Heightfield) vertex(x, z int) mgl.Vec3 {
+	return mgl.Vec3{
+		hf.Offset[0] + float32(x)*hf.Spacing,
+		hf.Offset[1] + hf.Heights[z*hf.Width+x],
+		hf.Offset[2] + float32(z)*hf.Spacing,
+	}
+}
+
+// TriangleCount returns the number of triangles in the heightfield.
+func (hf *Heightfield) TriangleCount() int {
+	if hf.Width < 2 || hf.Depth < 2 {
+		return 0
+	}
+	return hf.cellsX() * hf.cellsZ() * 2
+}
+
+// Triangle returns the world-space vertices of the triangle at index i.
+// Each cell has two triangles split along the diagonal from the cell's
+// (+X, -Z) corner to its (-X, +Z) corner, both wound to face up.
+func (hf *Heightfield) Triangle(i int) (mgl.Vec3, mgl.Vec3, mgl.Vec3) {
+	cell := i / 2
+	x := cell % hf.cellsX()
+	z := cell / hf.cellsX()
+	if i%2 == 0 {
+		return hf.vertex(x, z), hf.vertex(x, z+1), hf.vertex(x+1, z)
+	}
+	return hf.vertex(x+1, z), hf.vertex(x, z+1), hf.vertex(x+1, z+1)
+}
+
+// cellAt returns the cell under the world-space X and Z coordinates along
+// with how far across the cell the position is on each axis. The last
+// return value is false if the position is outside of the heightfield.
+func (hf *Heightfield) cellAt(x, z float32) (int, int, float32, float32, bool) {
+	if hf.Width < 2 || hf.Depth < 2 || hf.Spacing <= 0 {
+		return 0, 0, 0, 0, false
+	}
+	fx := (x - hf.Offset[0]) / hf.Spacing
+	fz := (z - hf.Offset[2]) / hf.Spacing
+	if fx < 0 || fz < 0 || fx > float32(hf.cellsX()) || fz > float32(hf.cellsZ()) {
+		return 0, 0, 0, 0, false
+	}
+
+	// positions on the far edges belong to the last cell
+	cx := int(fx)
+	if cx == hf.cellsX() {
+		cx--
+	}
+	cz := int(fz)
+	if cz == hf.cellsZ() {
+		cz--
+	}
+	return cx, cz, fx - float32(cx), fz - float32(cz), true
+}
+
+// HeightAt returns the world-space height of the heightfield's surface at
+// the world-space X and Z coordinates, which is useful for snapping objects
+// to the ground. The second return value is false if the position is
+// outside of the heightfield.
+func (hf *Heightfield) HeightAt(x, z float32) (float32, bool) {
+	cx, cz, fx, fz, ok := hf.cellAt(x, z)
+	if !ok {
+		return 0.0, false
+	}
+
+	h00 := hf.Heights[cz*hf.Width+cx]
+	h10 := hf.Heights[cz*hf.Width+cx+1]
+	h01 := hf.Heights[(cz+1)*hf.Width+cx]
+	h11 := hf.Heights[(cz+1)*hf.Width+cx+1]
+
+	// interpolate across whichever of the cell's triangles holds the position
+	var h float32
+	if fx+fz <= 1 {
+		h = h00 + (h10-h00)*fx + (h01-h00)*fz
+	} else {
+		h = h11 + (h01-h11)*(1-fx) + (h10-h11)*(1-fz)
+	}
+	return hf.Offset[1] + h, true
+}
+
+// NormalAt returns the surface normal of the heightfield at the world-space
+// X and Z coordinates. The second return value is false if the position is
+// outside of the heightfield.
+func (hf *Heightfield) NormalAt(x, z float32) (mgl.Vec3, bool) {
+	cx, cz, fx, fz, ok := hf.cellAt(x, z)
+	if !ok {
+		return mgl.Vec3{}, false
+	}
+
+	h00 := hf.Heights[cz*hf.Width+cx]
+	h10 := hf.Heights[cz*hf.Width+cx+1]
+	h01 := hf.Heights[(cz+1)*hf.Width+cx]
+	h11 := hf.Heights[(cz+1)*hf.Width+cx+1]
+
+	// the change in height across the triangle on each axis
+	var dx, dz float32
+	if fx+fz <= 1 {
+		dx = h10 - h00
+		dz = h01 - h00
+	} else {
+		dx = h11 - h01
+		dz = h11 - h10
+	}
+	return mgl.Vec3{-dx, hf.Spacing, -dz}.Normalize(), true
+}
+
+// CollideVsRay tests to see if a raycast intersects the heightfield and
+// returns the distance to the closest triangle hit.
+func (hf *Heightfield) CollideVsRay(ray *CollisionRay) (int, float32) {
+	result, hit := hf.CollideVsRayDetailed(ray)
+	return result, hit.Distance
+}
+
+// CollideVsRayDetailed tests to see if a raycast intersects the heightfield
+// and returns the distance, triangle index and barycentric coordinates of
+// the closest triangle hit. Only the cells the ray passes over are tested,
+// in the order the ray crosses them, so the first hit is the closest.
+func (hf *Heightfield) CollideVsRayDetailed(ray *CollisionRay) (int, TriangleHit) {
+	if !ray.valid() || hf.Width < 2 || hf.Depth < 2 || hf.Spacing <= 0 {
+		return NoIntersect, TriangleHit{}
+	}
+
+	// walk the grid in the heightfield's local space on the XZ plane
+	o := ray.Origin.Sub(hf.Offset)
+	d := ray.direction
+	size := [2]float32{float32(hf.cellsX()) * hf.Spacing, float32(hf.cellsZ()) * hf.Spacing}
+	origin := [2]float32{o[0], o[2]}
+	dir := [2]float32{d[0], d[2]}
+
+	// clip the ray to the heightfield's rectangle
+	tmin := float32(0.0)
+	tmax := float32(math.Inf(1))
+	for i := 0; i < 2; i++ {
+		if dir[i] == 0 {
+			if origin[i] < 0 || origin[i] > size[i] {
+				return NoIntersect, TriangleHit{}
+			}
+			continue
+		}
+		t1 := (0 - origin[i]) / dir[i]
+		t2 := (size[i] - origin[i]) / dir[i]
+		tmin = max32(tmin, min32(t1, t2))
+		tmax = min32(tmax, max32(t1, t2))
+	}
+	if tmin > tmax || !ray.inRange(tmin) {
+		return NoIntersect, TriangleHit{}
+	}
+
+	// set up the grid walk from the cell where the ray enters
+	cells := [2]int{hf.cellsX(), hf.cellsZ()}
+	var cell, step [2]int
+	var tNext, tDelta [2]float32
+	for i := 0; i < 2; i++ {
+		p := (origin[i] + dir[i]*tmin) / hf.Spacing
+		cell[i] = int(p)
+		if cell[i] < 0 {
+			cell[i] = 0
+		} else if cell[i] >= cells[i] {
+			cell[i] = cells[i] - 1
+		}
+
+		switch {
+		case dir[i] > 0:
+			step[i] = 1
+			tNext[i] = (float32(cell[i]+1)*hf.Spacing - origin[i]) / dir[i]
+			tDelta[i] = hf.Spacing / dir[i]
+		case dir[i] < 0:
+			step[i] = -1
+			tNext[i] = (float32(cell[i])*hf.Spacing - origin[i]) / dir[i]
+			tDelta[i] = -hf.Spacing / dir[i]
+		default:
+			tNext[i] = float32(math.Inf(1))
+			tDelta[i] = float32(math.Inf(1))
+		}
+	}
+
+	for cell[0] >= 0 && cell[0] < cells[0] && cell[1] >= 0 && cell[1] < cells[1] {
+		var closest TriangleHit
+		result := NoIntersect
+		first := (cell[1]*cells[0] + cell[0]) * 2
+		for tri := first; tri < first+2; tri++ {
+			v0, v1, v2 := hf.Triangle(tri)
+			hit, dist, u, v := RayVsTriangle(ray, v0, v1, v2, false)
+			if hit == NoIntersect {
+				continue
+			}
+			if result == NoIntersect || dist < closest.Distance {
+				result = Intersect
+				closest = TriangleHit{Distance: dist, U: u, V: v, Index: tri}
+			}
+		}
+		if result == Intersect {
+			return result, closest
+		}
+
+		// step into the next cell the ray crosses
+		var t float32
+		if tNext[0] < tNext[1] {
+			t = tNext[0]
+			cell[0] += step[0]
+			tNext[0] += tDelta[0]
+		} else {
+			t = tNext[1]
+			cell[1] += step[1]
+			tNext[1] += tDelta[1]
+		}
+		// a ray running straight up or down never leaves its cell
+		if math.IsInf(float64(t), 1) || t > tmax || !ray.inRange(t) {
+			break
+		}
+	}
+
+	return NoIntersect, TriangleHit{}
+}
+
+// cellRange returns the range of cells, inclusive, covered by the world-space
+// bounds on the XZ plane. The last return value is false if the bounds don't
+// overlap the heightfield.
+func (hf *Heightfield) cellRange(min, max mgl.Vec3) (int, int, int, int, bool) {
+	if hf.Width < 2 || hf.Depth < 2 || hf.Spacing <= 0 {
+		return 0, 0, 0, 0, false
+	}
+	minX := (min[0] - hf.Offset[0]) / hf.Spacing
+	maxX := (max[0] - hf.Offset[0]) / hf.Spacing
+	minZ := (min[2] - hf.Offset[2]) / hf.Spacing
+	maxZ := (max[2] - hf.Offset[2]) / hf.Spacing
+	if maxX < 0 || maxZ < 0 || minX > float32(hf.cellsX()) || minZ > float32(hf.cellsZ()) {
+		return 0, 0, 0, 0, false
+	}
+
+	x0 := int(max32(minX, 0))
+	z0 := int(max32(minZ, 0))
+	x1 := int(min32(maxX, float32(hf.cellsX()-1)))
+	z1 := int(min32(maxZ, float32(hf.cellsZ()-1)))
+	if x0 > hf.cellsX()-1 {
+		x0 = hf.cellsX() - 1
+	}
+	if z0 > hf.cellsZ()-1 {
+		z0 = hf.cellsZ() - 1
+	}
+	return x0, z0, x1, z1, true
+}
+
+// collideVsTriangles calls test with the triangles in the cells covered by
+// the world-space bounds and returns Intersect as soon as one passes.
+func (hf *Heightfield) collideVsTriangles(min, max mgl.Vec3, test func(a, b, c mgl.Vec3) bool) int {
+	x0, z0, x1, z1, ok := hf.cellRange(min, max)
+	if !ok {
+		return NoIntersect
+	}
+	for z := z0; z <= z1; z++ {
+		for x := x0; x <= x1; x++ {
+			first := (z*hf.cellsX() + x) * 2
+			for tri := first; tri < first+2; tri++ {
+				v0, v1, v2 := hf.Triangle(tri)
+				if test(v0, v1, v2) {
+					return Intersect
+				}
+			}
+		}
+	}
+	return NoIntersect
+}
+
+// CollideVsSphere tests a collision between a heightfield and a sphere.
+// Only the triangles under the sphere are tested.
+func (hf *Heightfield) CollideVsSphere(s *Sphere) int {
+	center := s.Center.Add(s.Offset)
+	r := mgl.Vec3{s.Radius, s.Radius, s.Radius}
+	return hf.collideVsTriangles(center.Sub(r), center.Add(r), func(a, b, c mgl.Vec3) bool {
+		return sphereVsTriangle(center, s.Radius, a, b, c)
+	})
+}
+
+// CollideVsAABBox tests a collision between a heightfield and an AABBox.
+// Only the triangles under the box are tested.
+func (hf *Heightfield) CollideVsAABBox(b *AABBox) int {
+	min := b.Min.Add(b.Offset)
+	max := b.Max.Add(b.Offset)
+	return hf.collideVsTriangles(min, max, func(v0, v1, v2 mgl.Vec3) bool {
+		return aabbVsTriangle(min, max, v0, v1, v2)
+	})
+}
+
+// CollideVsPlane tests a collision between a heightfield and a plane. The
+// heightfield only fails to intersect if all of its samples lie behind
+// the plane.
+func (hf *Heightfield) CollideVsPlane(p *Plane) int {
+	for z := 0; z < hf.Depth; z++ {
+		for x := 0; x < hf.Width; x++ {
+			if p.Distance(hf.vertex(x, z)) >= 0 {
+				return Intersect
+			}
+		}
+	}
+	return NoIntersect
+}
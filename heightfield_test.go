@@ -0,0 +1,181 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestRampHeightfield makes a 4x4 heightfield with 2 unit spacing that
+// rises one unit in height for every sample along the X axis.
+func newTestRampHeightfield() *Heightfield {
+	heights := make([]float32, 16)
+	for z := 0; z < 4; z++ {
+		for x := 0; x < 4; x++ {
+			heights[z*4+x] = float32(x)
+		}
+	}
+	return NewHeightfield(4, 4, heights, 2.0)
+}
+
+func TestHeightfieldSampling(t *testing.T) {
+	hf := newTestRampHeightfield()
+	hf.SetOffset3f(10.0, 5.0, 10.0)
+
+	tests := []struct {
+		x, z     float32
+		expected float32
+	}{
+		{10.0, 10.0, 5.0},
+		{11.0, 11.0, 5.5},
+		{13.5, 11.5, 6.75},
+		{16.0, 16.0, 8.0},
+	}
+	for _, test := range tests {
+		h, ok := hf.HeightAt(test.x, test.z)
+		if !ok || !mgl.FloatEqualThreshold(h, test.expected, 1e-4) {
+			t.Errorf("Heightfield.HeightAt(%f, %f) returned %f instead of %f", test.x, test.z, h, test.expected)
+		}
+	}
+
+	if _, ok := hf.HeightAt(9.0, 11.0); ok {
+		t.Error("Heightfield.HeightAt() returned a height outside of the heightfield.")
+	}
+
+	// the ramp rises half a unit for every unit along X in both triangles of a cell
+	expectedNormal := mgl.Vec3{-1, 2, 0}.Normalize()
+	for _, p := range []mgl.Vec2{{10.5, 10.5}, {11.5, 11.5}} {
+		n, ok := hf.NormalAt(p[0], p[1])
+		if !ok || !n.ApproxEqualThreshold(expectedNormal, 1e-4) {
+			t.Errorf("Heightfield.NormalAt(%v) returned %v instead of %v", p, n, expectedNormal)
+		}
+	}
+}
+
+func TestHeightfieldFromImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 3, 2))
+	img.SetGray(2, 1, color.Gray{Y: 255})
+
+	hf := NewHeightfieldFromImage(img, 1.0, 10.0)
+	if err := hf.Validate(); err != nil {
+		t.Fatalf("NewHeightfieldFromImage() made an invalid heightfield: %v", err)
+	}
+	if hf.Width != 3 || hf.Depth != 2 {
+		t.Errorf("NewHeightfieldFromImage() made a %dx%d heightfield instead of 3x2", hf.Width, hf.Depth)
+	}
+	if h, _ := hf.HeightAt(2.0, 1.0); !mgl.FloatEqualThreshold(h, 10.0, 1e-4) {
+		t.Errorf("NewHeightfieldFromImage() scaled a white pixel to %f instead of 10", h)
+	}
+	if h, _ := hf.HeightAt(0.0, 0.0); h != 0.0 {
+		t.Errorf("NewHeightfieldFromImage() scaled a black pixel to %f instead of 0", h)
+	}
+}
+
+func TestHeightfieldCollisionVsRay(t *testing.T) {
+	hf := newTestRampHeightfield()
+
+	// straight down onto the ramp
+	ray, _ := NewCollisionRay(mgl.Vec3{3.0, 10.0, 3.0}, mgl.Vec3{0, -1, 0})
+	result, dist := hf.CollideVsRay(ray)
+	if result != Intersect || !mgl.FloatEqualThreshold(dist, 8.5, 1e-4) {
+		t.Errorf("Heightfield.CollideVsRay() didn't hit the ramp from above correctly: %d %f", result, dist)
+	}
+
+	// skim across the grid from the low side into the rising ramp
+	ray, _ = NewCollisionRay(mgl.Vec3{-5.0, 2.5, 1.0}, mgl.Vec3{1, 0, 0})
+	result, hit := hf.CollideVsRayDetailed(ray)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 10.0, 1e-4) {
+		t.Errorf("Heightfield.CollideVsRayDetailed() didn't hit the ramp from the side correctly: %d %f", result, hit.Distance)
+	}
+	if hit.Index/2 != 2 {
+		t.Errorf("Heightfield.CollideVsRayDetailed() hit triangle %d which isn't in the third cell", hit.Index)
+	}
+
+	// too short to reach the ramp
+	ray.MaxDistance = 9.0
+	if result, _ := hf.CollideVsRay(ray); result != NoIntersect {
+		t.Error("Heightfield.CollideVsRay() hit beyond the ray's MaxDistance.")
+	}
+
+	// above the ramp heading away from it
+	ray, _ = NewCollisionRay(mgl.Vec3{3.0, 10.0, 3.0}, mgl.Vec3{-1, 1, 0})
+	if result, _ := hf.CollideVsRay(ray); result != NoIntersect {
+		t.Error("Heightfield.CollideVsRay() hit when the ray was heading away.")
+	}
+
+	// completely off the side of the grid
+	ray, _ = NewCollisionRay(mgl.Vec3{-1.0, 10.0, 3.0}, mgl.Vec3{0, -1, 0})
+	if result, _ := hf.CollideVsRay(ray); result != NoIntersect {
+		t.Error("Heightfield.CollideVsRay() hit when the ray was beside the grid.")
+	}
+}
+
+func TestHeightfieldCollisionVsVerticalRayMiss(t *testing.T) {
+	hf := newTestRampHeightfield()
+
+	// an unbounded ray pointing straight up from above the grid never
+	// leaves its starting cell, so the walk has to stop on its own
+	ray, _ := NewCollisionRay(mgl.Vec3{3.0, 10.0, 3.0}, mgl.Vec3{0, 1, 0})
+	done := make(chan int, 1)
+	go func() {
+		result, _ := hf.CollideVsRayDetailed(ray)
+		done <- result
+	}()
+
+	select {
+	case result := <-done:
+		if result != NoIntersect {
+			t.Error("Heightfield.CollideVsRayDetailed() hit with a vertical ray pointing away from the grid.")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Heightfield.CollideVsRayDetailed() didn't return for a vertical ray that misses.")
+	}
+}
+
+func TestHeightfieldCollisionVsShapes(t *testing.T) {
+	hf := newTestRampHeightfield()
+
+	// the surface is at a height of 1.5 at x=3
+	sphere := Sphere{Center: mgl.Vec3{3.0, 2.0, 3.0}, Radius: 1.0}
+	if hf.CollideVsSphere(&sphere) != Intersect {
+		t.Error("Heightfield.CollideVsSphere() indicated a sphere didn't collide that should have.")
+	}
+	if Collide(hf, &sphere) != Intersect {
+		t.Error("Collide() indicated a sphere didn't collide with the heightfield.")
+	}
+	sphere.Center[1] = 3.0
+	if hf.CollideVsSphere(&sphere) != NoIntersect {
+		t.Error("Heightfield.CollideVsSphere() indicated a sphere collided that should not have.")
+	}
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-0.5, -0.5, -0.5}
+	b1.Max = mgl.Vec3{0.5, 0.5, 0.5}
+	b1.Offset = mgl.Vec3{3.0, 1.5, 3.0}
+	if hf.CollideVsAABBox(&b1) != Intersect {
+		t.Error("Heightfield.CollideVsAABBox() indicated a box didn't collide that should have.")
+	}
+	b1.Offset = mgl.Vec3{3.0, 2.5, 3.0}
+	if hf.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("Heightfield.CollideVsAABBox() indicated a box collided that should not have.")
+	}
+	b1.Offset = mgl.Vec3{-3.0, 0.0, 3.0}
+	if hf.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("Heightfield.CollideVsAABBox() indicated a box beside the grid collided.")
+	}
+
+	p := NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 2.5, 0})
+	if hf.CollideVsPlane(p) != Intersect {
+		t.Error("Heightfield.CollideVsPlane() indicated a plane didn't collide that should have.")
+	}
+	p = NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 3.5, 0})
+	if hf.CollideVsPlane(p) != NoIntersect {
+		t.Error("Heightfield.CollideVsPlane() indicated a plane collided that should not have.")
+	}
+}
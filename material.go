@@ -0,0 +1,20 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+// Material describes the surface of a shape so that the results of
+// collision tests can be used to pick physics responses and effects such
+// as footstep sounds.
+type Material struct {
+	// Friction is the coefficient of friction of the surface.
+	Friction float32
+
+	// Restitution is how bouncy the surface is, from 0 for no bounce to 1
+	// for a perfectly elastic bounce.
+	Restitution float32
+
+	// SurfaceType is an application defined identifier for the kind of
+	// surface (e.g. grass, metal or water).
+	SurfaceType int
+}
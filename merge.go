@@ -0,0 +1,132 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// MergeSquares turns a 2d grid of solid cells into a small set of AABSquares
+// covering exactly the solid cells using greedy meshing, which is far
+// cheaper to test than one square per cell. The grid is indexed as
+// grid[y][x] and each cell is tileSize units across with cell (0, 0) at
+// the origin. Rows may have different lengths.
+func MergeSquares(grid [][]bool, tileSize float32) []*AABSquare {
+	used := make([][]bool, len(grid))
+	for y := range grid {
+		used[y] = make([]bool, len(grid[y]))
+	}
+	open := func(x, y int) bool {
+		return y < len(grid) && x < len(grid[y]) && grid[y][x] && !used[y][x]
+	}
+
+	var squares []*AABSquare
+	for y := range grid {
+		for x := range grid[y] {
+			if !open(x, y) {
+				continue
+			}
+
+			// grow the run along X and then grow it along Y for as long
+			// as the whole run is free
+			w := 1
+			for open(x+w, y) {
+				w++
+			}
+			h := 1
+			for rowOpen(open, x, y+h, w) {
+				h++
+			}
+
+			for j := y; j < y+h; j++ {
+				for i := x; i < x+w; i++ {
+					used[j][i] = true
+				}
+			}
+
+			s := NewAABSquare()
+			s.Min = mgl.Vec2{float32(x), float32(y)}.Mul(tileSize)
+			s.Max = mgl.Vec2{float32(x + w), float32(y + h)}.Mul(tileSize)
+			squares = append(squares, s)
+		}
+	}
+	return squares
+}
+
+// MergeVoxels turns the solid voxels of a VoxelGrid into a small set of
+// AABBoxes covering exactly the solid voxels using greedy meshing, which is
+// far cheaper to test than one box per voxel. The boxes share the grid's
+// Offset.
+func MergeVoxels(vg *VoxelGrid) []*AABBox {
+	if vg.Width <= 0 || vg.Height <= 0 || vg.Depth <= 0 {
+		return nil
+	}
+	used := make([]bool, vg.Width*vg.Height*vg.Depth)
+	open := func(x, y, z int) bool {
+		return vg.IsSolid(x, y, z) && !used[vg.index(x, y, z)]
+	}
+
+	var boxes []*AABBox
+	for z := 0; z < vg.Depth; z++ {
+		for y := 0; y < vg.Height; y++ {
+			for x := 0; x < vg.Width; x++ {
+				if !open(x, y, z) {
+					continue
+				}
+
+				// grow the run along X, then grow that into a rectangle
+				// along Y and finally grow the rectangle along Z
+				w := 1
+				for open(x+w, y, z) {
+					w++
+				}
+				h := 1
+				for rowOpen(func(i, j int) bool { return open(i, j, z) }, x, y+h, w) {
+					h++
+				}
+				d := 1
+				for sliceOpen(open, x, y, z+d, w, h) {
+					d++
+				}
+
+				for k := z; k < z+d; k++ {
+					for j := y; j < y+h; j++ {
+						for i := x; i < x+w; i++ {
+							used[vg.index(i, j, k)] = true
+						}
+					}
+				}
+
+				b := NewAABBox()
+				b.Min = mgl.Vec3{float32(x), float32(y), float32(z)}.Mul(vg.CellSize)
+				b.Max = mgl.Vec3{float32(x + w), float32(y + h), float32(z + d)}.Mul(vg.CellSize)
+				b.Offset = vg.Offset
+				boxes = append(boxes, b)
+			}
+		}
+	}
+	return boxes
+}
+
+// rowOpen returns true if the w cells starting at (x, y) are all open.
+func rowOpen(open func(x, y int) bool, x, y, w int) bool {
+	for i := x; i < x+w; i++ {
+		if !open(i, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// sliceOpen returns true if the w by h cells starting at (x, y, z) are all open.
+func sliceOpen(open func(x, y, z int) bool, x, y, z, w, h int) bool {
+	for j := y; j < y+h; j++ {
+		for i := x; i < x+w; i++ {
+			if !open(i, j, z) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestMergeSquares(t *testing.T) {
+	// an L shape with a separate single tile
+	grid := [][]bool{
+		{true, true, true, false, true},
+		{true, true, true, false, false},
+		{true, false, false, false},
+	}
+
+	squares := MergeSquares(grid, 2.0)
+	if len(squares) != 3 {
+		t.Fatalf("MergeSquares() returned %d squares instead of 3: %v", len(squares), squares)
+	}
+
+	var area float32
+	for _, s := range squares {
+		size := s.Max.Sub(s.Min)
+		area += size[0] * size[1]
+	}
+	if area != 8*4.0 {
+		t.Errorf("MergeSquares() covered an area of %f instead of 32", area)
+	}
+
+	if squares[0].Min != (mgl.Vec2{0, 0}) || squares[0].Max != (mgl.Vec2{6, 4}) {
+		t.Errorf("MergeSquares() didn't merge the 3x2 block first: %v %v", squares[0].Min, squares[0].Max)
+	}
+
+	// every solid cell center is covered by exactly one square
+	for y := range grid {
+		for x := range grid[y] {
+			p := mgl.Vec2{float32(x)*2.0 + 1.0, float32(y)*2.0 + 1.0}
+			count := 0
+			for _, s := range squares {
+				if s.IntersectPoint(&p) {
+					count++
+				}
+			}
+			if (grid[y][x] && count != 1) || (!grid[y][x] && count != 0) {
+				t.Errorf("MergeSquares() covered cell (%d, %d) %d times", x, y, count)
+			}
+		}
+	}
+}
+
+func TestMergeVoxels(t *testing.T) {
+	vg := newTestVoxelFloor()
+	vg.SetOffset3f(-4.0, -1.0, -4.0)
+
+	boxes := MergeVoxels(vg)
+	if len(boxes) != 2 {
+		t.Fatalf("MergeVoxels() returned %d boxes instead of 2", len(boxes))
+	}
+	if boxes[0].Min != (mgl.Vec3{0, 0, 0}) || boxes[0].Max != (mgl.Vec3{8, 1, 8}) {
+		t.Errorf("MergeVoxels() didn't merge the floor into one box: %v %v", boxes[0].Min, boxes[0].Max)
+	}
+	if boxes[1].Min != (mgl.Vec3{4, 1, 4}) || boxes[1].Max != (mgl.Vec3{5, 2, 5}) {
+		t.Errorf("MergeVoxels() didn't make a box for the pillar: %v %v", boxes[1].Min, boxes[1].Max)
+	}
+	if boxes[1].Offset != vg.Offset {
+		t.Errorf("MergeVoxels() didn't copy the grid's offset: %v", boxes[1].Offset)
+	}
+
+	// a hollow 3x3x3 cube can't be a single box
+	vg = NewVoxelGrid(3, 3, 3, 1.0)
+	solid := 0
+	for z := 0; z < 3; z++ {
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 3; x++ {
+				if x != 1 || y != 1 || z != 1 {
+					vg.Set(x, y, z, true)
+					solid++
+				}
+			}
+		}
+	}
+	var volume float32
+	for _, b := range MergeVoxels(vg) {
+		size := b.Max.Sub(b.Min)
+		volume += size[0] * size[1] * size[2]
+	}
+	if volume != float32(solid) {
+		t.Errorf("MergeVoxels() covered a volume of %f instead of %d", volume, solid)
+	}
+}
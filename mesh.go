@@ -0,0 +1,176 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// TriangleSet is implemented by colliders made up of triangles, such as Mesh,
+// so that triangle based algorithms can work with any of them.
+type TriangleSet interface {
+	// TriangleCount returns the number of triangles in the set.
+	TriangleCount() int
+
+	// Triangle returns the world-space vertices of the triangle at index i.
+	Triangle(i int) (mgl.Vec3, mgl.Vec3, mgl.Vec3)
+}
+
+// Mesh is a collision shape made up of indexed triangles, such as static
+// level geometry. Every three entries in Indices make up one triangle.
+type Mesh struct {
+	// Vertices holds the local-space vertices of the mesh.
+	Vertices []mgl.Vec3
+
+	// Indices holds three vertex indexes for each triangle in the mesh.
+	Indices []uint32
+
+	// Offset is the world-space location of the that can be considered an offset to the vertices
+	Offset mgl.Vec3
+
+	// CullBackface makes ray casts ignore hits on the back face of triangles.
+	CullBackface bool
+
+	// Tags provides a way to label a mesh in a custom application
+	// (e.g. labelling a collision as "wall" or "floor").
+	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// mesh is hit, such as the model it was built from.
+	UserData interface{}
+
+	// Material describes the surface of the mesh.
+	Material Material
+}
+
+// NewMesh creates a new Mesh object from the vertices and triangle indexes.
+func NewMesh(vertices []mgl.Vec3, indices []uint32) *Mesh {
+	m := new(Mesh)
+	m.Vertices = vertices
+	m.Indices = indices
+	return m
+}
+
+// SetOffset changes the offset of the collision object.
+func (m *Mesh) SetOffset(offset *mgl.Vec3) {
+	m.Offset = *offset
+}
+
+// SetOffset3f changes the offset of the collision object.
+func (m *Mesh) SetOffset3f(x, y, z float32) {
+	m.Offset[0] = x
+	m.Offset[1] = y
+	m.Offset[2] = z
+}
+
+// Validate checks the Mesh for non-finite values and indexes that don't
+// refer to a vertex or don't come in sets of three.
+func (m *Mesh) Validate() error {
+	for _, v := range m.Vertices {
+		if err := validateFinite("Mesh", "Vertices", v[:]); err != nil {
+			return err
+		}
+	}
+	if err := validateFinite("Mesh", "Offset", m.Offset[:]); err != nil {
+		return err
+	}
+	if len(m.Indices)%3 != 0 {
+		return &ShapeError{Shape: "Mesh", Field: "Indices", Err: ErrInvalidIndex}
+	}
+	for _, index := range m.Indices {
+		if int(index) >= len(m.Vertices) {
+			return &ShapeError{Shape: "Mesh", Field: "Indices", Err: ErrInvalidIndex}
+		}
+	}
+	return nil
+}
+
+// TriangleCount returns the number of triangles in the mesh.
+func (m *Mesh) TriangleCount() int {
+	return len(m.Indices) / 3
+}
+
+// Triangle returns the world-space vertices of the triangle at index i.
+func (m *Mesh) Triangle(i int) (mgl.Vec3, mgl.Vec3, mgl.Vec3) {
+	v0 := m.Vertices[m.Indices[i*3]].Add(m.Offset)
+	v1 := m.Vertices[m.Indices[i*3+1]].Add(m.Offset)
+	v2 := m.Vertices[m.Indices[i*3+2]].Add(m.Offset)
+	return v0, v1, v2
+}
+
+// CollideVsRay tests to see if a raycast intersects the mesh and returns
+// the distance to the closest triangle hit.
+func (m *Mesh) CollideVsRay(ray *CollisionRay) (int, float32) {
+	result, hit := m.CollideVsRayDetailed(ray)
+	return result, hit.Distance
+}
+
+// CollideVsRayDetailed tests to see if a raycast intersects the mesh and
+// returns the distance, triangle index and barycentric coordinates of the
+// closest triangle hit.
+func (m *Mesh) CollideVsRayDetailed(ray *CollisionRay) (int, TriangleHit) {
+	var closest TriangleHit
+	result := NoIntersect
+
+	triCount := m.TriangleCount()
+	for i := 0; i < triCount; i++ {
+		v0, v1, v2 := m.Triangle(i)
+		hit, dist, u, v := RayVsTriangle(ray, v0, v1, v2, m.CullBackface)
+		if hit == NoIntersect {
+			continue
+		}
+		if result == NoIntersect || dist < closest.Distance {
+			result = Intersect
+			closest.Distance = dist
+			closest.U = u
+			closest.V = v
+			closest.Index = i
+		}
+	}
+
+	return result, closest
+}
+
+// CollideVsSphere tests a collision between a mesh and a sphere.
+func (m *Mesh) CollideVsSphere(s *Sphere) int {
+	center := s.Center.Add(s.Offset)
+	triCount := m.TriangleCount()
+	for i := 0; i < triCount; i++ {
+		v0, v1, v2 := m.Triangle(i)
+		if sphereVsTriangle(center, s.Radius, v0, v1, v2) {
+			return Intersect
+		}
+	}
+	return NoIntersect
+}
+
+// CollideVsAABBox tests a collision between a mesh and an AABBox.
+func (m *Mesh) CollideVsAABBox(b *AABBox) int {
+	min := b.Min.Add(b.Offset)
+	max := b.Max.Add(b.Offset)
+	triCount := m.TriangleCount()
+	for i := 0; i < triCount; i++ {
+		v0, v1, v2 := m.Triangle(i)
+		if aabbVsTriangle(min, max, v0, v1, v2) {
+			return Intersect
+		}
+	}
+	return NoIntersect
+}
+
+// CollideVsPlane tests a collision between a mesh and a plane. The mesh
+// only fails to intersect if all of its triangles lie behind the plane;
+// vertices that no triangle uses are ignored.
+func (m *Mesh) CollideVsPlane(p *Plane) int {
+	for _, index := range m.Indices[:m.TriangleCount()*3] {
+		if p.Distance(m.Vertices[index].Add(m.Offset)) >= 0 {
+			return Intersect
+		}
+	}
+	return NoIntersect
+}
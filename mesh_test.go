@@ -0,0 +1,104 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestQuadMesh makes a 2x2 quad in the y=0 plane facing +y.
+func newTestQuadMesh() *Mesh {
+	verts := []mgl.Vec3{
+		{-1, 0, -1},
+		{1, 0, -1},
+		{1, 0, 1},
+		{-1, 0, 1},
+	}
+	indices := []uint32{0, 2, 1, 0, 3, 2}
+	return NewMesh(verts, indices)
+}
+
+func TestMeshCollisionVsRay(t *testing.T) {
+	m := newTestQuadMesh()
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.5, 10.0, 0.5}
+	r1.SetDirection(mgl.Vec3{0.0, -1.0, 0.0})
+
+	intersect, hit := m.CollideVsRayDetailed(&r1)
+	if intersect != Intersect {
+		t.Fatal("Mesh.CollideVsRay() indicated a ray didn't intersect that should have.")
+	}
+	if !mgl.FloatEqual(hit.Distance, 10.0) {
+		t.Errorf("Mesh.CollideVsRay() returned the wrong distance: %f", hit.Distance)
+	}
+	if hit.Index != 0 {
+		t.Errorf("Mesh.CollideVsRay() returned the wrong triangle: %d", hit.Index)
+	}
+
+	r1.Origin = mgl.Vec3{-0.5, 10.0, 0.5}
+	intersect, hit = m.CollideVsRayDetailed(&r1)
+	if intersect != Intersect || hit.Index != 1 {
+		t.Errorf("Mesh.CollideVsRay() didn't hit the second triangle: %d", hit.Index)
+	}
+
+	// from below with backface culling
+	m.CullBackface = true
+	r1.Origin = mgl.Vec3{0.5, -10.0, 0.5}
+	r1.SetDirection(mgl.Vec3{0.0, 1.0, 0.0})
+	intersect, _ = m.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Mesh.CollideVsRay() indicated a ray intersected a culled back face.")
+	}
+
+	// move the mesh out of the way
+	m.CullBackface = false
+	m.SetOffset3f(0, 0, 5)
+	intersect, _ = m.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Mesh.CollideVsRay() indicated a ray intersected an offset mesh.")
+	}
+}
+
+func TestMeshCollisionVsShapes(t *testing.T) {
+	m := newTestQuadMesh()
+
+	sphere := Sphere{Center: mgl.Vec3{0.0, 0.5, 0.0}, Radius: 1.0}
+	if Collide(m, &sphere) != Intersect {
+		t.Error("Collide() indicated a sphere didn't collide with a mesh that it should have.")
+	}
+	sphere.Offset = mgl.Vec3{0, 2, 0}
+	if m.CollideVsSphere(&sphere) != NoIntersect {
+		t.Error("Mesh.CollideVsSphere() indicated a sphere collided that should not have.")
+	}
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-0.5, -0.5, -0.5}
+	b1.Max = mgl.Vec3{0.5, 0.5, 0.5}
+	if Collide(m, &b1) != Intersect {
+		t.Error("Collide() indicated a box didn't collide with a mesh that it should have.")
+	}
+	b1.Offset = mgl.Vec3{3, 0, 0}
+	if m.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("Mesh.CollideVsAABBox() indicated a box collided that should not have.")
+	}
+
+	p := NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 1, 0})
+	if m.CollideVsPlane(p) != NoIntersect {
+		t.Error("Mesh.CollideVsPlane() indicated a plane intersected that should not have.")
+	}
+	p = NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 0, 0})
+	if m.CollideVsPlane(p) != Intersect {
+		t.Error("Mesh.CollideVsPlane() indicated a plane didn't intersect that should have.")
+	}
+
+	// a stray vertex above the plane that no triangle uses
+	m.Vertices = append(m.Vertices, mgl.Vec3{0, 5, 0})
+	p = NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 1, 0})
+	if m.CollideVsPlane(p) != NoIntersect {
+		t.Error("Mesh.CollideVsPlane() intersected a plane with a vertex no triangle uses.")
+	}
+}
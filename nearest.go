@@ -0,0 +1,307 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// NearestHit describes a collider found by a nearest query.
+type NearestHit struct {
+	// Collider is the object that was found.
+	Collider Collider
+
+	// Index is the position of Collider in the set that was searched.
+	// Queries through a World set Handle instead and use an Index of -1.
+	Index int
+
+	// Handle is the handle of Collider when the query was made through a World.
+	Handle Handle
+
+	// Distance is the distance from the query point to the surface of
+	// Collider. Points inside a collider have a distance of zero.
+	Distance float32
+
+	// Point is the point on Collider closest to the query point.
+	Point mgl.Vec3
+
+	// UserData is the UserData of Collider.
+	UserData interface{}
+
+	// Material is the Material of Collider.
+	Material Material
+}
+
+// PointDistance returns the distance from the point to the surface of the
+// collider and the point on the collider closest to it. Points inside a
+// solid collider have a distance of zero and are their own closest point.
+// Boxes, spheres, triangles, meshes, heightfields, voxel grids, ellipsoids,
+// cylinders and cones are supported; the last return value is false for
+// other colliders.
+func PointDistance(p mgl.Vec3, c Collider) (float32, mgl.Vec3, bool) {
+	return pointDistanceWithin(p, c, 0)
+}
+
+// pointDistanceWithin is PointDistance for a search that only cares about
+// colliders within limit of the point, which lets voxel grids stop looking
+// early. A limit of zero or less doesn't limit the search.
+func pointDistanceWithin(p mgl.Vec3, c Collider, limit float32) (float32, mgl.Vec3, bool) {
+	switch s := c.(type) {
+	case *AABBox:
+		closest := ColliderBounds(s).ClosestPoint(p)
+		return closest.Sub(p).Len(), closest, true
+	case *Sphere:
+		center := s.Center.Add(s.Offset)
+		toPoint := p.Sub(center)
+		dist := toPoint.Len()
+		if dist <= s.Radius {
+			return 0.0, p, true
+		}
+		return dist - s.Radius, center.Add(toPoint.Mul(s.Radius / dist)), true
+	case *Ellipsoid:
+		dist, _, closest := gjkDistance(pointShape(p), s)
+		return dist, closest, true
+	case *Cylinder:
+		dist, _, closest := gjkDistance(pointShape(p), s)
+		return dist, closest, true
+	case *Cone:
+		dist, _, closest := gjkDistance(pointShape(p), s)
+		return dist, closest, true
+	case *Triangle:
+		return pointDistanceVsTriangles(p, singleTriangle{s})
+	case *VoxelGrid:
+		return pointDistanceVsVoxels(p, s, limit)
+	case TriangleSet:
+		return pointDistanceVsTriangles(p, s)
+	}
+	return 0.0, p, false
+}
+
+// pointDistanceVsTriangles returns the distance from the point to the
+// closest of the triangles and the closest point on it.
+func pointDistanceVsTriangles(p mgl.Vec3, triangles TriangleSet) (float32, mgl.Vec3, bool) {
+	triCount := triangles.TriangleCount()
+	if triCount == 0 {
+		return 0.0, p, false
+	}
+
+	var best mgl.Vec3
+	var bestSq float32
+	for i := 0; i < triCount; i++ {
+		v0, v1, v2 := triangles.Triangle(i)
+		closest := closestPointOnTriangle(p, v0, v1, v2)
+		toPoint := p.Sub(closest)
+		if distSq := toPoint.Dot(toPoint); i == 0 || distSq < bestSq {
+			best, bestSq = closest, distSq
+		}
+	}
+	return best.Sub(p).Len(), best, true
+}
+
+// pointDistanceVsVoxels returns the distance from the point to the closest
+// solid voxel and the closest point on it. The search starts at the voxel
+// nearest the point and grows outward a shell of voxels at a time until no
+// voxel left can be closer than the best one found or than limit.
+func pointDistanceVsVoxels(p mgl.Vec3, vg *VoxelGrid, limit float32) (float32, mgl.Vec3, bool) {
+	dims := vg.dims()
+	if dims[0] <= 0 || dims[1] <= 0 || dims[2] <= 0 || vg.CellSize <= 0 {
+		return 0.0, p, false
+	}
+
+	var center [3]int
+	for i := 0; i < 3; i++ {
+		c := int(math.Floor(float64((p[i] - vg.Offset[i]) / vg.CellSize)))
+		if c < 0 {
+			c = 0
+		} else if c >= dims[i] {
+			c = dims[i] - 1
+		}
+		center[i] = c
+	}
+
+	found := false
+	var best mgl.Vec3
+	var bestSq float32
+	visit := func(x, y, z int) {
+		if x < 0 || x >= dims[0] || !vg.IsSolid(x, y, z) {
+			return
+		}
+		min, max := vg.VoxelBounds(x, y, z)
+		closest := Bounds{Min: min, Max: max}.ClosestPoint(p)
+		toPoint := p.Sub(closest)
+		if distSq := toPoint.Dot(toPoint); !found || distSq < bestSq {
+			best, bestSq = closest, distSq
+			found = true
+		}
+	}
+
+	for r := 0; ; r++ {
+		var lo, hi [3]int
+		for i := 0; i < 3; i++ {
+			lo[i] = center[i] - r
+			if lo[i] < 0 {
+				lo[i] = 0
+			}
+			hi[i] = center[i] + r
+			if hi[i] > dims[i]-1 {
+				hi[i] = dims[i] - 1
+			}
+		}
+
+		// only the voxels exactly r steps from the center are new; rows
+		// inside the shell only have them at either end
+		for z := lo[2]; z <= hi[2]; z++ {
+			for y := lo[1]; y <= hi[1]; y++ {
+				if absInt(z-center[2]) < r && absInt(y-center[1]) < r {
+					visit(center[0]-r, y, z)
+					visit(center[0]+r, y, z)
+					continue
+				}
+				for x := lo[0]; x <= hi[0]; x++ {
+					visit(x, y, z)
+				}
+			}
+		}
+
+		// the closest any voxel outside the searched block can be
+		exhausted := true
+		lower := float32(math.Inf(1))
+		for i := 0; i < 3; i++ {
+			if lo[i] > 0 {
+				exhausted = false
+				lower = min32(lower, p[i]-(vg.Offset[i]+float32(lo[i])*vg.CellSize))
+			}
+			if hi[i] < dims[i]-1 {
+				exhausted = false
+				lower = min32(lower, vg.Offset[i]+float32(hi[i]+1)*vg.CellSize-p[i])
+			}
+		}
+		if exhausted || (found && lower > 0 && lower*lower > bestSq) || (limit > 0 && lower > limit) {
+			break
+		}
+	}
+	if !found {
+		return 0.0, p, false
+	}
+	return best.Sub(p).Len(), best, true
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// addNearest tests the collider against the point and, if it is no further
+// away than maxDist and among the k closest found so far, inserts it into
+// the hits in result[start:] which are kept sorted by distance. A k of zero
+// keeps every hit and a maxDist of zero or less doesn't limit the distance.
+// The collider's bounds are checked first so that colliders that are too
+// far away are rejected quickly.
+func addNearest(p mgl.Vec3, c Collider, index int, h Handle, k int, maxDist float32, result []NearestHit, start int) []NearestHit {
+	found := result[start:]
+	full := k > 0 && len(found) >= k
+	lower := ColliderBounds(c).ClosestPoint(p).Sub(p).Len()
+	if (maxDist > 0 && lower > maxDist) || (full && lower > found[k-1].Distance) {
+		return result
+	}
+
+	limit := maxDist
+	if full && (limit <= 0 || found[k-1].Distance < limit) {
+		limit = found[k-1].Distance
+	}
+	dist, point, okay := pointDistanceWithin(p, c, limit)
+	if !okay || (maxDist > 0 && dist > maxDist) {
+		return result
+	}
+	hit := NearestHit{Collider: c, Index: index, Handle: h, Distance: dist, Point: point}
+	if full && !nearer(&hit, &found[k-1]) {
+		return result
+	}
+	props := colliderProperties(c)
+	hit.UserData, hit.Material = props.userData, props.material
+
+	// the last hit falls off the end when there are already k of them
+	if !full {
+		result = append(result, hit)
+		found = result[start:]
+	}
+	i := len(found) - 1
+	for i > 0 && nearer(&hit, &found[i-1]) {
+		found[i] = found[i-1]
+		i--
+	}
+	found[i] = hit
+	return result
+}
+
+// nearer returns true if the hit a should be sorted before b: it is closer,
+// or it is at the same distance and came first in its set.
+func nearer(a, b *NearestHit) bool {
+	if a.Distance != b.Distance {
+		return a.Distance < b.Distance
+	}
+	if a.Handle != b.Handle {
+		return a.Handle < b.Handle
+	}
+	return a.Index < b.Index
+}
+
+// Nearest returns the collider closest to the point. Colliders whose TagSet
+// doesn't pass all of the filters are skipped. The second return value is
+// false if none of the colliders are supported by PointDistance.
+func Nearest(p mgl.Vec3, colliders []Collider, filters ...TagFilter) (NearestHit, bool) {
+	var buf [1]NearestHit
+	hits := buf[:0]
+	for i, c := range colliders {
+		if passesTagFilters(c, filters) {
+			hits = addNearest(p, c, i, 0, 1, 0, hits, 0)
+		}
+	}
+	if len(hits) == 0 {
+		return NearestHit{}, false
+	}
+	return hits[0], true
+}
+
+// NearestK appends up to k of the colliders closest to the point to result
+// sorted by distance, closest first, and returns the result. Colliders at
+// the same distance keep the order they had in the colliders slice.
+// Colliders whose TagSet doesn't pass all of the filters are skipped.
+// Nothing is allocated if result has room for k more hits.
+func NearestK(p mgl.Vec3, k int, colliders []Collider, result []NearestHit, filters ...TagFilter) []NearestHit {
+	if k <= 0 {
+		return result
+	}
+	start := len(result)
+	for i, c := range colliders {
+		if passesTagFilters(c, filters) {
+			result = addNearest(p, c, i, 0, k, 0, result, start)
+		}
+	}
+	return result
+}
+
+// WithinRadius appends all of the colliders whose surface is no further
+// than radius from the point to result sorted by distance, closest first,
+// and returns the result. Colliders at the same distance keep the order
+// they had in the colliders slice. Colliders whose TagSet doesn't pass all
+// of the filters are skipped. Nothing is allocated if result has room for
+// the hits.
+func WithinRadius(p mgl.Vec3, radius float32, colliders []Collider, result []NearestHit, filters ...TagFilter) []NearestHit {
+	if radius < 0 {
+		return result
+	}
+	radius = max32(radius, rayEpsilon)
+	start := len(result)
+	for i, c := range colliders {
+		if passesTagFilters(c, filters) {
+			result = addNearest(p, c, i, 0, 0, radius, result, start)
+		}
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math/rand"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestPointDistance(t *testing.T) {
+	p := mgl.Vec3{3.0, 0.0, 0.0}
+
+	box := newTestWorldBox(0, 0, 0)
+	dist, closest, okay := PointDistance(p, box)
+	if !okay || !mgl.FloatEqualThreshold(dist, 2.5, 1e-4) || !closest.ApproxEqualThreshold(mgl.Vec3{0.5, 0, 0}, 1e-4) {
+		t.Errorf("PointDistance() returned %v, %v, %v for a box", dist, closest, okay)
+	}
+	if dist, _, _ = PointDistance(mgl.Vec3{0.2, 0.1, 0.0}, box); dist != 0 {
+		t.Errorf("PointDistance() returned %v for a point inside a box", dist)
+	}
+
+	s := Sphere{Center: mgl.Vec3{0, 1, 0}, Radius: 1.0}
+	dist, closest, okay = PointDistance(mgl.Vec3{0, 4, 0}, &s)
+	if !okay || !mgl.FloatEqualThreshold(dist, 2.0, 1e-4) || !closest.ApproxEqualThreshold(mgl.Vec3{0, 2, 0}, 1e-4) {
+		t.Errorf("PointDistance() returned %v, %v, %v for a sphere", dist, closest, okay)
+	}
+
+	c := NewCylinder()
+	c.Radius = 1.0
+	c.HalfHeight = 2.0
+	dist, _, okay = PointDistance(p, c)
+	if !okay || !mgl.FloatEqualThreshold(dist, 2.0, 1e-3) {
+		t.Errorf("PointDistance() returned %v, %v for a cylinder", dist, okay)
+	}
+
+	vertices := []mgl.Vec3{
+		{-5.0, 0.0, -5.0}, {5.0, 0.0, -5.0}, {5.0, 0.0, 5.0}, {-5.0, 0.0, 5.0},
+	}
+	floor := NewMesh(vertices, []uint32{0, 2, 1, 0, 3, 2})
+	dist, closest, okay = PointDistance(mgl.Vec3{1, 3, 7}, floor)
+	if !okay || !mgl.FloatEqualThreshold(dist, 3.6055513, 1e-4) || !closest.ApproxEqualThreshold(mgl.Vec3{1, 0, 5}, 1e-4) {
+		t.Errorf("PointDistance() returned %v, %v, %v for a mesh", dist, closest, okay)
+	}
+
+	vg := newTestVoxelFloor()
+	dist, _, okay = PointDistance(mgl.Vec3{4.5, 4.0, 4.5}, vg)
+	if !okay || !mgl.FloatEqualThreshold(dist, 2.0, 1e-4) {
+		t.Errorf("PointDistance() returned %v, %v for a voxel grid", dist, okay)
+	}
+
+	if _, _, okay = PointDistance(p, nil); okay {
+		t.Error("PointDistance() returned true for an unsupported collider.")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	colliders := []Collider{
+		newTestWorldBox(10, 0, 0),
+		newTestWorldBox(-3, 0, 0),
+		&Sphere{Center: mgl.Vec3{0, 5, 0}, Radius: 2.0},
+		newTestWorldBox(0, 0, 3),
+	}
+
+	hit, okay := Nearest(mgl.Vec3{}, colliders)
+	if !okay || hit.Index != 1 || !mgl.FloatEqualThreshold(hit.Distance, 2.5, 1e-4) {
+		t.Errorf("Nearest() returned %v, %v instead of the box at index 1", hit, okay)
+	}
+
+	// the sphere and the box at index 3 are the same distance away
+	hits := NearestK(mgl.Vec3{}, 3, colliders, nil)
+	if len(hits) != 3 || hits[0].Index != 1 || hits[1].Index != 3 || hits[2].Index != 2 {
+		t.Errorf("NearestK() returned %v instead of the three closest colliders", hits)
+	}
+	if hits = NearestK(mgl.Vec3{}, 10, colliders, nil); len(hits) != 4 || hits[3].Index != 0 {
+		t.Errorf("NearestK() returned %v instead of all of the colliders", hits)
+	}
+
+	hits = WithinRadius(mgl.Vec3{}, 3.0, colliders, nil)
+	if len(hits) != 3 || hits[0].Collider != colliders[1] {
+		t.Errorf("WithinRadius() returned %v instead of the three closest colliders", hits)
+	}
+	if hits = WithinRadius(mgl.Vec3{}, 1.0, colliders, nil); len(hits) != 0 {
+		t.Errorf("WithinRadius() returned %v for a radius with nothing in it", hits)
+	}
+}
+
+func TestWorldQueryNearest(t *testing.T) {
+	w := NewWorld(NewSweepAndPruneBroadphase())
+	far := w.Add(newTestWorldBox(10, 0, 0))
+	near := w.Add(newTestWorldBox(-3, 0, 0))
+	other := w.Add(newTestWorldBox(0, 0, 4))
+	w.SetLayers(near, 2, AllLayers)
+
+	hit, okay := w.QueryNearest(mgl.Vec3{}, 0, AllLayers)
+	if !okay || hit.Handle != near || hit.Index != -1 || !mgl.FloatEqualThreshold(hit.Distance, 2.5, 1e-4) {
+		t.Errorf("World.QueryNearest() returned %v, %v instead of the nearest box", hit, okay)
+	}
+	if hit, okay = w.QueryNearest(mgl.Vec3{}, 0, DefaultLayer); !okay || hit.Handle != other {
+		t.Errorf("World.QueryNearest() returned %v, %v instead of the nearest box on the layer", hit, okay)
+	}
+	if _, okay = w.QueryNearest(mgl.Vec3{}, 2.0, AllLayers); okay {
+		t.Error("World.QueryNearest() found a box past the max distance.")
+	}
+
+	// results are appended after what is already in the buffer
+	result := make([]NearestHit, 1, 4)
+	result = w.QueryNearestK(mgl.Vec3{}, 2, 0, AllLayers, result)
+	if len(result) != 3 || result[1].Handle != near || result[2].Handle != other {
+		t.Errorf("World.QueryNearestK() returned %v instead of the two nearest boxes", result)
+	}
+
+	hits := w.QueryRadius(mgl.Vec3{9, 0, 0}, 1.0, AllLayers, nil)
+	if len(hits) != 1 || hits[0].Handle != far {
+		t.Errorf("World.QueryRadius() returned %v instead of the far box", hits)
+	}
+}
+
+func TestNearestAllocs(t *testing.T) {
+	colliders := []Collider{
+		newTestWorldBox(10, 0, 0),
+		newTestWorldBox(-3, 0, 0),
+		&Sphere{Center: mgl.Vec3{0, 5, 0}, Radius: 2.0},
+		newTestWorldBox(0, 0, 3),
+	}
+
+	hits := make([]NearestHit, 0, len(colliders))
+	allocs := testing.AllocsPerRun(100, func() {
+		hits = NearestK(mgl.Vec3{}, 2, colliders, hits[:0])
+		hits = WithinRadius(mgl.Vec3{}, 3.0, colliders, hits[:0])
+		Nearest(mgl.Vec3{}, colliders)
+	})
+	if allocs != 0 {
+		t.Errorf("nearest queries allocated %v times with a buffer", allocs)
+	}
+}
+
+func TestPointDistanceVsVoxelsMatchesFullScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	vg := NewVoxelGrid(16, 12, 10, 0.5)
+	vg.SetOffset3f(-3, 1, 2)
+	for i := 0; i < 40; i++ {
+		vg.Set(rng.Intn(vg.Width), rng.Intn(vg.Height), rng.Intn(vg.Depth), true)
+	}
+
+	for i := 0; i < 500; i++ {
+		p := mgl.Vec3{
+			rng.Float32()*16 - 8,
+			rng.Float32()*14 - 2,
+			rng.Float32()*12 - 1,
+		}
+
+		var want float32 = -1
+		for z := 0; z < vg.Depth; z++ {
+			for y := 0; y < vg.Height; y++ {
+				for x := 0; x < vg.Width; x++ {
+					if !vg.IsSolid(x, y, z) {
+						continue
+					}
+					min, max := vg.VoxelBounds(x, y, z)
+					d := Bounds{Min: min, Max: max}.ClosestPoint(p).Sub(p).Len()
+					if want < 0 || d < want {
+						want = d
+					}
+				}
+			}
+		}
+
+		dist, _, okay := PointDistance(p, vg)
+		if !okay || !mgl.FloatEqualThreshold(dist, want, 1e-4) {
+			t.Fatalf("PointDistance(%v) returned %f, %v instead of %f", p, dist, okay, want)
+		}
+	}
+}
+
+func TestNearestVoxelsWithinRadius(t *testing.T) {
+	vg := NewVoxelGrid(64, 64, 64, 1.0)
+	vg.Set(0, 0, 0, true)
+	vg.Set(2, 0, 0, true)
+	vg.Set(63, 63, 63, true)
+	colliders := []Collider{vg}
+
+	hits := WithinRadius(mgl.Vec3{2.5, 0.5, 3.0}, 3.0, colliders, nil)
+	if len(hits) != 1 || !mgl.FloatEqualThreshold(hits[0].Distance, 2.0, 1e-4) {
+		t.Errorf("WithinRadius() returned %v instead of the voxel at (2, 0, 0)", hits)
+	}
+	if hits = WithinRadius(mgl.Vec3{32, 32, 32}, 3.0, colliders, nil); len(hits) != 0 {
+		t.Errorf("WithinRadius() returned %v for a radius with no voxels in it", hits)
+	}
+	if hits = NearestK(mgl.Vec3{60, 60, 60}, 1, colliders, nil); len(hits) != 1 ||
+		!mgl.FloatEqualThreshold(hits[0].Distance, 5.196152, 1e-4) {
+		t.Errorf("NearestK() returned %v instead of the voxel at (63, 63, 63)", hits)
+	}
+}
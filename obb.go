@@ -11,7 +11,7 @@ import (
 
 // OBBox is a oriented bounding box shape defined by a center ('Offset') and
 // a HalfSize. HalfSize is used instead of Min/Max corners because the math is
-// quicker.
+// quicker. A zero value box has the identity orientation.
 type OBBox struct {
 	// HalfSize holds the cube's half-sizes along each of its local axes.
 	HalfSize mgl.Vec3
@@ -26,11 +26,23 @@ type OBBox struct {
 	// Tags provides a way to label an OBB geometry in a custom application
 	// (e.g. labelling a collision as "wall" or "floor").
 	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// box is hit, such as the game object that owns it.
+	UserData interface{}
+
+	// Material describes the surface of the box.
+	Material Material
 }
 
 // NewOBBox creates a new OBBox object
 func NewOBBox() *OBBox {
 	obb := new(OBBox)
+	obb.orientation = mgl.QuatIdent()
 	obb.transform = mgl.Ident4()
 	return obb
 }
@@ -76,6 +88,62 @@ func (obb *OBBox) SetOrientation(q mgl.Quat) {
 	obb.syncOffset()
 }
 
+// GetOrientation gets the rotation of the box.
+func (obb *OBBox) GetOrientation() mgl.Quat {
+	return obb.rotation()
+}
+
+// rotation returns the orientation of the box. A zero value box that wasn't
+// made with NewOBBox has the identity orientation.
+func (obb *OBBox) rotation() mgl.Quat {
+	return orientationOrIdent(obb.orientation)
+}
+
+// matrix returns the transform of the box, using the identity orientation
+// for a zero value box like rotation does.
+func (obb *OBBox) matrix() mgl.Mat4 {
+	if obb.orientation == (mgl.Quat{}) {
+		return mgl.Translate3D(obb.Offset[0], obb.Offset[1], obb.Offset[2])
+	}
+	return obb.transform
+}
+
+// Corners returns the world-space corners of the box. Bit 0 of a corner's
+// index is set if it is on the positive side of the box's local X axis, bit 1
+// for the Y axis and bit 2 for the Z axis.
+func (obb *OBBox) Corners() [8]mgl.Vec3 {
+	var corners [8]mgl.Vec3
+	for c := 0; c < 8; c++ {
+		p := obb.Offset
+		for i := 0; i < 3; i++ {
+			if c&(1<<uint(i)) != 0 {
+				p = p.Add(obb.axis(i).Mul(obb.HalfSize[i]))
+			} else {
+				p = p.Sub(obb.axis(i).Mul(obb.HalfSize[i]))
+			}
+		}
+		corners[c] = p
+	}
+	return corners
+}
+
+// Validate checks the OBBox for non-finite values, negative half sizes and
+// an orientation that isn't a unit quaternion.
+func (obb *OBBox) Validate() error {
+	if err := validateFinite("OBBox", "HalfSize", obb.HalfSize[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("OBBox", "Offset", obb.Offset[:]); err != nil {
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		if obb.HalfSize[i] < 0 {
+			return &ShapeError{Shape: "OBBox", Field: "HalfSize", Err: ErrNegativeSize}
+		}
+	}
+	return validateOrientation("OBBox", obb.rotation())
+}
+
 func (obb *OBBox) syncOffset() {
 	obb.transform[12] = obb.Offset[0]
 	obb.transform[13] = obb.Offset[1]
@@ -86,7 +154,8 @@ func (obb *OBBox) syncOffset() {
 func (obb *OBBox) CollideVsSphere(sphere *Sphere) int {
 	// transform the center of the sphere into cube coordinates
 	position := sphere.Offset.Add(sphere.Center)
-	relCenter := transformInverse(&obb.transform, &position)
+	m := obb.matrix()
+	relCenter := transformInverse(&m, &position)
 
 	// check to see if we can exclude contact
 	if fabs32(relCenter[0])-sphere.Radius > obb.HalfSize[0] ||
@@ -117,3 +186,41 @@ func (obb *OBBox) CollideVsSphere(sphere *Sphere) int {
 
 	return Intersect
 }
+
+// ClassifyVsPlane returns Front if the OBBox is completely in front of the
+// plane, Back if it's completely behind it and Straddling otherwise.
+func (obb *OBBox) ClassifyVsPlane(p *Plane) Classification {
+	// project the half sizes along each of the box's axes onto the plane normal
+	var radius float32
+	for i := 0; i < 3; i++ {
+		radius += obb.HalfSize[i] * fabs32(p.Normal.Dot(obb.axis(i)))
+	}
+	return classifyExtentVsPlane(p.Distance(obb.Offset), radius)
+}
+
+// ClassifyVsFrustum returns Inside if the OBBox is completely inside the
+// frustum, Outside if it's completely outside of one of the frustum's planes
+// and Intersecting otherwise.
+func (obb *OBBox) ClassifyVsFrustum(f *Frustum) Classification {
+	return f.classify(obb.ClassifyVsPlane)
+}
+
+// axis returns the world-space direction of one of the box's local axes.
+func (obb *OBBox) axis(i int) mgl.Vec3 {
+	m := obb.matrix()
+	return mgl.Vec3{m[i*4], m[i*4+1], m[i*4+2]}
+}
+
+// support returns the corner of the box furthest along d.
+func (obb *OBBox) support(d mgl.Vec3) mgl.Vec3 {
+	p := obb.Offset
+	for i := 0; i < 3; i++ {
+		axis := obb.axis(i)
+		if axis.Dot(d) >= 0 {
+			p = p.Add(axis.Mul(obb.HalfSize[i]))
+		} else {
+			p = p.Sub(axis.Mul(obb.HalfSize[i]))
+		}
+	}
+	return p
+}
@@ -57,4 +57,18 @@ func TestOOBCollisionVsSphere(t *testing.T) {
 		t.Error("OBBox.CollideVsSphere() indicated a sphere collided that should not have.")
 	}
 
+	zero := OBBox{HalfSize: mgl.Vec3{1, 1, 1}, Offset: mgl.Vec3{5, 0, 0}}
+	if zero.GetOrientation() != mgl.QuatIdent() {
+		t.Errorf("OBBox.GetOrientation() returned %v for a zero value box instead of the identity.", zero.GetOrientation())
+	}
+	if err := zero.Validate(); err != nil {
+		t.Errorf("OBBox.Validate() failed for a zero value box: %v", err)
+	}
+	if corners := zero.Corners(); corners[0] != (mgl.Vec3{4, -1, -1}) || corners[7] != (mgl.Vec3{6, 1, 1}) {
+		t.Errorf("OBBox.Corners() returned the wrong corners for a zero value box: %v", corners)
+	}
+	sphere = Sphere{Center: mgl.Vec3{6.5, 0.0, 0.0}, Radius: 1.0}
+	if zero.CollideVsSphere(&sphere) != Intersect {
+		t.Error("OBBox.CollideVsSphere() didn't collide with a zero value box.")
+	}
 }
@@ -0,0 +1,63 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// chunksPerWorker is how many pieces work is split into for each worker so
+// that workers that finish early can pick up the rest of the work.
+const chunksPerWorker = 4
+
+// chunkCount returns how many chunks n items should be split into for the
+// workers; never more than there are items.
+func chunkCount(n, workers int) int {
+	chunks := workers * chunksPerWorker
+	if chunks > n {
+		chunks = n
+	}
+	return chunks
+}
+
+// chunkRange returns the range of items, from start up to but not including
+// end, that make up the chunk when n items are split into chunks pieces.
+func chunkRange(n, chunks, chunk int) (int, int) {
+	return n * chunk / chunks, n * (chunk + 1) / chunks
+}
+
+// runParallel calls fn once for every chunk from 0 up to chunks using up to
+// workers goroutines and waits for them to finish. Chunks are handed out in
+// whatever order the workers ask for them, so fn should only write to
+// memory that belongs to its chunk; callers then combine the results in
+// chunk order so that they don't depend on scheduling.
+func runParallel(workers, chunks int, fn func(chunk int)) {
+	if workers > chunks {
+		workers = chunks
+	}
+	if workers <= 1 {
+		for c := 0; c < chunks; c++ {
+			fn(c)
+		}
+		return
+	}
+
+	var next int32 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				c := int(atomic.AddInt32(&next, 1))
+				if c >= chunks {
+					return
+				}
+				fn(c)
+			}
+		}()
+	}
+	wg.Wait()
+}
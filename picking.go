@@ -0,0 +1,44 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"errors"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// NewPickingRay builds a CollisionRay that runs from the near plane to the far
+// plane of a camera underneath a mouse position, which is useful for picking
+// objects in a scene with a ray cast. It works for both perspective and
+// orthographic projection matrixes.
+//
+// mouseX and mouseY are measured in pixels from the top-left corner of the
+// viewport, as most windowing toolkits report them. viewport holds the x, y,
+// width and height of the viewport like the parameters to glViewport.
+func NewPickingRay(mouseX, mouseY float32, viewport [4]int, view, projection mgl.Mat4) (*CollisionRay, error) {
+	// flip the y axis so that it's measured from the bottom of the viewport
+	winX := float32(viewport[0]) + mouseX
+	winY := float32(viewport[1]) + float32(viewport[3]) - mouseY
+
+	near, err := mgl.UnProject(mgl.Vec3{winX, winY, 0.0}, view, projection, viewport[0], viewport[1], viewport[2], viewport[3])
+	if err != nil {
+		return nil, err
+	}
+	far, err := mgl.UnProject(mgl.Vec3{winX, winY, 1.0}, view, projection, viewport[0], viewport[1], viewport[2], viewport[3])
+	if err != nil {
+		return nil, err
+	}
+
+	if near.ApproxEqual(far) {
+		return nil, errors.New("glider: the near and far planes of the projection unproject to the same point")
+	}
+
+	ray := new(CollisionRay)
+	ray.SetSegment(near, far)
+	if err := ray.Validate(); err != nil {
+		return nil, err
+	}
+	return ray, nil
+}
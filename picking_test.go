@@ -0,0 +1,87 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestNewPickingRayPerspective(t *testing.T) {
+	viewport := [4]int{0, 0, 800, 600}
+	view := mgl.LookAtV(mgl.Vec3{0, 0, 10}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(mgl.DegToRad(60.0), 800.0/600.0, 1.0, 100.0)
+
+	// the center of the screen looks straight down -z
+	ray, err := NewPickingRay(400, 300, viewport, view, projection)
+	if err != nil {
+		t.Fatalf("NewPickingRay() returned an error: %v", err)
+	}
+	if !ray.Origin.ApproxEqualThreshold(mgl.Vec3{0, 0, 9}, 1e-4) {
+		t.Errorf("NewPickingRay() returned the wrong origin: %v", ray.Origin)
+	}
+	if !ray.GetDirection().ApproxEqualThreshold(mgl.Vec3{0, 0, -1}, 1e-4) {
+		t.Errorf("NewPickingRay() returned the wrong direction: %v", ray.GetDirection())
+	}
+	if !mgl.FloatEqualThreshold(ray.MaxDistance, 99.0, 1e-2) {
+		t.Errorf("NewPickingRay() returned the wrong max distance: %f", ray.MaxDistance)
+	}
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	b1.Max = mgl.Vec3{1.0, 1.0, 1.0}
+	intersect, _ := b1.CollideVsRay(ray)
+	if intersect != Intersect {
+		t.Error("NewPickingRay() built a ray from the center of the screen that missed the box at the origin.")
+	}
+
+	// the top-left corner of the screen should miss the box and point up and left
+	ray, err = NewPickingRay(0, 0, viewport, view, projection)
+	if err != nil {
+		t.Fatalf("NewPickingRay() returned an error: %v", err)
+	}
+	dir := ray.GetDirection()
+	if dir[0] >= 0 || dir[1] <= 0 || dir[2] >= 0 {
+		t.Errorf("NewPickingRay() returned the wrong direction for the top-left corner: %v", dir)
+	}
+	intersect, _ = b1.CollideVsRay(ray)
+	if intersect != NoIntersect {
+		t.Error("NewPickingRay() built a ray from the corner of the screen that hit the box at the origin.")
+	}
+}
+
+func TestNewPickingRayOrthographic(t *testing.T) {
+	viewport := [4]int{0, 0, 800, 600}
+	view := mgl.LookAtV(mgl.Vec3{0, 0, 10}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Ortho(-4.0, 4.0, -3.0, 3.0, 1.0, 100.0)
+
+	// every ray from an orthographic camera points the same way
+	ray, err := NewPickingRay(0, 0, viewport, view, projection)
+	if err != nil {
+		t.Fatalf("NewPickingRay() returned an error: %v", err)
+	}
+	if !ray.Origin.ApproxEqualThreshold(mgl.Vec3{-4, 3, 9}, 1e-4) {
+		t.Errorf("NewPickingRay() returned the wrong origin: %v", ray.Origin)
+	}
+	if !ray.GetDirection().ApproxEqualThreshold(mgl.Vec3{0, 0, -1}, 1e-4) {
+		t.Errorf("NewPickingRay() returned the wrong direction: %v", ray.GetDirection())
+	}
+
+	// a viewport that doesn't start at the window origin
+	viewport = [4]int{100, 50, 800, 600}
+	ray, err = NewPickingRay(400, 300, viewport, view, projection)
+	if err != nil {
+		t.Fatalf("NewPickingRay() returned an error: %v", err)
+	}
+	if !ray.Origin.ApproxEqualThreshold(mgl.Vec3{0, 0, 9}, 1e-4) {
+		t.Errorf("NewPickingRay() returned the wrong origin for an offset viewport: %v", ray.Origin)
+	}
+
+	// singular matrixes can't be unprojected
+	_, err = NewPickingRay(0, 0, viewport, view, mgl.Mat4{})
+	if err == nil {
+		t.Error("NewPickingRay() didn't return an error for a singular projection.")
+	}
+}
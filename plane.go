@@ -14,6 +14,16 @@ type Plane struct {
 
 	// D is the plane constant, considered to be the distance from the origin.
 	D float32
+
+	// TagSet holds labels for the plane interned by a TagRegistry.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// plane is hit, such as the level it belongs to.
+	UserData interface{}
+
+	// Material describes the surface of the plane.
+	Material Material
 }
 
 // NewPlaneFromNormalAndPoint makes a new Plane object based on a normal
@@ -29,3 +39,34 @@ func NewPlaneFromNormalAndPoint(normal, point mgl.Vec3) *Plane {
 func (p *Plane) Distance(v mgl.Vec3) float32 {
 	return p.D + p.Normal.Dot(v)
 }
+
+// Validate checks the Plane for non-finite values and a zero-length normal.
+func (p *Plane) Validate() error {
+	if err := validateFinite("Plane", "Normal", p.Normal[:]); err != nil {
+		return err
+	}
+	if !isFinite32(p.D) {
+		return &ShapeError{Shape: "Plane", Field: "D", Err: ErrNonFinite}
+	}
+	if p.Normal[0] == 0 && p.Normal[1] == 0 && p.Normal[2] == 0 {
+		return &ShapeError{Shape: "Plane", Field: "Normal", Err: ErrZeroNormal}
+	}
+	return nil
+}
+
+// CollideVsRay tests to see if a raycast intersects the plane and returns
+// the distance along the ray to the hit. Planes are two-sided for ray casts
+// and a ray running parallel to the plane never intersects it.
+func (p *Plane) CollideVsRay(ray *CollisionRay) (int, float32) {
+	denom := p.Normal.Dot(ray.direction)
+	if denom > -rayEpsilon && denom < rayEpsilon {
+		return NoIntersect, 0.0
+	}
+
+	t := -p.Distance(ray.Origin) / denom
+	if t < 0.0 || !ray.inRange(t) {
+		return NoIntersect, 0.0
+	}
+
+	return Intersect, t
+}
@@ -0,0 +1,60 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestPlaneCollisionVsRay(t *testing.T) {
+	// Plane @ {10, 0, 0}   Normal---> {1, 0, 0}
+	p := NewPlaneFromNormalAndPoint(mgl.Vec3{1.0, 0.0, 0.0}, mgl.Vec3{10, 0, 0})
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+
+	intersect, dist := p.CollideVsRay(&r1)
+	if intersect != Intersect {
+		t.Error("Plane.CollideVsRay() indicated a ray didn't intersect that should have.")
+	}
+	if !mgl.FloatEqual(dist, 10.0) {
+		t.Errorf("Plane.CollideVsRay() returned the wrong distance: %f", dist)
+	}
+
+	// stop short of the plane
+	r1.MaxDistance = 9.0
+	intersect, _ = p.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Plane.CollideVsRay() indicated a ray intersected beyond its MaxDistance.")
+	}
+	r1.MaxDistance = 0.0
+
+	// hit the back of the plane at an angle
+	r1.Origin = mgl.Vec3{20.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{-1.0, 1.0, 0.0})
+	intersect, dist = p.CollideVsRay(&r1)
+	if intersect != Intersect {
+		t.Error("Plane.CollideVsRay() indicated a ray didn't intersect that should have.")
+	}
+	if !mgl.FloatEqualThreshold(dist, 14.142136, 1e-4) {
+		t.Errorf("Plane.CollideVsRay() returned the wrong distance: %f", dist)
+	}
+
+	// pointing away
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+	intersect, _ = p.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Plane.CollideVsRay() indicated a ray pointed away intersected.")
+	}
+
+	// parallel to the plane
+	r1.SetDirection(mgl.Vec3{0.0, 1.0, 0.0})
+	intersect, _ = p.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Plane.CollideVsRay() indicated a parallel ray intersected.")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// CollisionRay2D represents a simple ray for casting in 2d collision tests.
+type CollisionRay2D struct {
+	// Origin is the start of the ray
+	Origin mgl.Vec2
+
+	// MaxDistance is the furthest distance along the ray that a hit will be
+	// reported at. A value of zero means the ray is unbounded.
+	MaxDistance float32
+
+	// direction is the unit vector representing the direction of the ray
+	direction mgl.Vec2
+}
+
+// NewCollisionRay2D creates a new CollisionRay2D starting at origin and
+// pointing in direction. An error is returned if the resulting ray is
+// not valid.
+func NewCollisionRay2D(origin, direction mgl.Vec2) (*CollisionRay2D, error) {
+	cr := new(CollisionRay2D)
+	cr.Origin = origin
+	cr.SetDirection(direction)
+	if err := cr.Validate(); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// SetDirection sets the direction of the collision ray. Will be normalized.
+// A zero-length or non-finite direction leaves the ray without a direction
+// so that it never hits anything; Validate can be used to detect this.
+func (cr *CollisionRay2D) SetDirection(d mgl.Vec2) {
+	dLen := float32(math.Sqrt(float64(d[0]*d[0] + d[1]*d[1])))
+	if dLen == 0 || !isFinite32(dLen) {
+		cr.direction = mgl.Vec2{}
+		return
+	}
+	cr.direction = d.Mul(1.0 / dLen)
+}
+
+// GetDirection gets the direction of the collision ray.
+func (cr *CollisionRay2D) GetDirection() mgl.Vec2 {
+	return cr.direction
+}
+
+// SetSegment turns the collision ray into a line segment running from start
+// to end so that hits beyond end are not reported.
+func (cr *CollisionRay2D) SetSegment(start, end mgl.Vec2) {
+	delta := end.Sub(start)
+	cr.Origin = start
+	cr.SetDirection(delta)
+	cr.MaxDistance = delta.Len()
+}
+
+// Validate returns a ShapeError wrapping ErrInvalidRay if the ray has no
+// usable direction, has a non-finite origin or has a negative or non-finite
+// MaxDistance.
+func (cr *CollisionRay2D) Validate() error {
+	if !cr.valid() {
+		return &ShapeError{Shape: "CollisionRay2D", Field: "direction", Err: ErrInvalidRay}
+	}
+	if validateFinite("CollisionRay2D", "Origin", cr.Origin[:]) != nil {
+		return &ShapeError{Shape: "CollisionRay2D", Field: "Origin", Err: ErrInvalidRay}
+	}
+	if cr.MaxDistance < 0 || !isFinite32(cr.MaxDistance) {
+		return &ShapeError{Shape: "CollisionRay2D", Field: "MaxDistance", Err: ErrInvalidRay}
+	}
+	return nil
+}
+
+// valid returns true if the ray has a direction to cast in.
+func (cr *CollisionRay2D) valid() bool {
+	return cr.direction[0] != 0 || cr.direction[1] != 0
+}
+
+// inRange returns true if the distance t along the ray is within the
+// ray's MaxDistance.
+func (cr *CollisionRay2D) inRange(t float32) bool {
+	return cr.MaxDistance <= 0 || t <= cr.MaxDistance
+}
@@ -0,0 +1,151 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+// RayHit describes a collider that was hit by a ray cast.
+type RayHit struct {
+	// Collider is the object that was hit.
+	Collider Collider
+
+	// Index is the position of Collider in the set that was cast against.
+	// Ray casts through a World set Handle instead and use an Index of -1.
+	Index int
+
+	// Handle is the handle of Collider when the ray was cast through a World.
+	Handle Handle
+
+	// Distance is the distance along the ray to where it enters the collider.
+	// Rays that start inside a collider have a distance of zero.
+	Distance float32
+
+	// UserData is the UserData of Collider.
+	UserData interface{}
+
+	// Material is the Material of Collider.
+	Material Material
+}
+
+// newRayHit builds a RayHit for the collider, filling in its properties.
+func newRayHit(c Collider, index int, h Handle, dist float32) RayHit {
+	hit := RayHit{Collider: c, Index: index, Handle: h, Distance: dist}
+	props := colliderProperties(c)
+	hit.UserData, hit.Material = props.userData, props.material
+	return hit
+}
+
+// rayHitsByDistance sorts RayHit slices by distance. Hits at the same
+// distance are sorted by Index and then Handle, which keeps them in the
+// order they were cast in.
+type rayHitsByDistance []RayHit
+
+func (hits rayHitsByDistance) Swap(i, j int) { hits[i], hits[j] = hits[j], hits[i] }
+
+func (hits rayHitsByDistance) Less(i, j int) bool {
+	if hits[i].Distance != hits[j].Distance {
+		return hits[i].Distance < hits[j].Distance
+	}
+	if hits[i].Index != hits[j].Index {
+		return hits[i].Index < hits[j].Index
+	}
+	return hits[i].Handle < hits[j].Handle
+}
+
+// siftDown moves the hit at root down the heap held in hits[:n].
+func (hits rayHitsByDistance) siftDown(root, n int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && hits.Less(child, child+1) {
+			child++
+		}
+		if !hits.Less(root, child) {
+			return
+		}
+		hits.Swap(root, child)
+		root = child
+	}
+}
+
+// sortRayHits heap sorts the hits in place. The slice belongs to the caller,
+// so handing it to sort.Sort would allocate; sorting the concrete type
+// doesn't. Less never finds two hits equal, so the order is still stable.
+func sortRayHits(hits rayHitsByDistance) {
+	n := len(hits)
+	for i := n/2 - 1; i >= 0; i-- {
+		hits.siftDown(i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		hits.Swap(0, end)
+		hits.siftDown(0, end)
+	}
+}
+
+// castRay tests the ray against a single collider and normalizes the
+// distance returned so that a ray starting inside reports zero.
+func castRay(ray *CollisionRay, c Collider) (int, float32) {
+	result, dist := c.CollideVsRay(ray)
+	if result == NoIntersect {
+		return NoIntersect, 0.0
+	}
+	if dist < 0.0 {
+		dist = 0.0
+	}
+	return Intersect, dist
+}
+
+// RayCastAll casts the ray against all of the colliders, appends every hit
+// to result sorted by distance, closest first, and returns the result.
+// Colliders hit at the same distance keep the order they had in the
+// colliders slice. Colliders whose TagSet doesn't pass all of the filters
+// are skipped. Nothing is allocated if result has room for the hits.
+func RayCastAll(ray *CollisionRay, colliders []Collider, result []RayHit, filters ...TagFilter) []RayHit {
+	start := len(result)
+	for i, c := range colliders {
+		if !passesTagFilters(c, filters) {
+			continue
+		}
+		hit, dist := castRay(ray, c)
+		if hit == Intersect {
+			result = append(result, newRayHit(c, i, 0, dist))
+		}
+	}
+	sortRayHits(result[start:])
+	return result
+}
+
+// RayCastFirst casts the ray against all of the colliders and returns only
+// the closest hit. The ray is shortened to the closest hit found so far as
+// the colliders are tested so that further objects can be rejected early.
+// Colliders whose TagSet doesn't pass all of the filters are skipped.
+// The second return value is false if nothing was hit.
+func RayCastFirst(ray *CollisionRay, colliders []Collider, filters ...TagFilter) (RayHit, bool) {
+	var closest RayHit
+	found := false
+
+	bounded := *ray
+	for i, c := range colliders {
+		if !passesTagFilters(c, filters) {
+			continue
+		}
+		result, dist := castRay(&bounded, c)
+		if result == NoIntersect {
+			continue
+		}
+		if !found || dist < closest.Distance {
+			closest = newRayHit(c, i, 0, dist)
+			found = true
+
+			// nothing beyond this hit matters any more; a zero distance
+			// can't be used since it means unbounded so stop early instead.
+			if dist <= 0.0 {
+				break
+			}
+			bounded.MaxDistance = dist
+		}
+	}
+
+	return closest, found
+}
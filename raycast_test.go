@@ -0,0 +1,161 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func newTestRayCastColliders() []Collider {
+	far := NewAABBox()
+	far.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	far.Max = mgl.Vec3{1.0, 1.0, 1.0}
+	far.Offset = mgl.Vec3{20.0, 0.0, 0.0}
+
+	near := NewSphere()
+	near.Radius = 1.0
+	near.Offset = mgl.Vec3{5.0, 0.0, 0.0}
+
+	missed := NewAABBox()
+	missed.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	missed.Max = mgl.Vec3{1.0, 1.0, 1.0}
+	missed.Offset = mgl.Vec3{10.0, 10.0, 0.0}
+
+	middle := NewAABBox()
+	middle.Min = mgl.Vec3{-1.0, -1.0, -1.0}
+	middle.Max = mgl.Vec3{1.0, 1.0, 1.0}
+	middle.Offset = mgl.Vec3{10.0, 0.0, 0.0}
+
+	return []Collider{far, near, missed, middle}
+}
+
+func TestRayCastAll(t *testing.T) {
+	colliders := newTestRayCastColliders()
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+
+	hits := RayCastAll(&r1, colliders, nil)
+	if len(hits) != 3 {
+		t.Fatalf("RayCastAll() returned %d hits instead of 3.", len(hits))
+	}
+
+	expectedIndexes := []int{1, 3, 0}
+	expectedDistances := []float32{4.0, 9.0, 19.0}
+	for i, hit := range hits {
+		if hit.Index != expectedIndexes[i] || hit.Collider != colliders[hit.Index] {
+			t.Errorf("RayCastAll() hit %d was collider %d instead of %d.", i, hit.Index, expectedIndexes[i])
+		}
+		if !mgl.FloatEqual(hit.Distance, expectedDistances[i]) {
+			t.Errorf("RayCastAll() hit %d had distance %f instead of %f.", i, hit.Distance, expectedDistances[i])
+		}
+	}
+
+	// bounded rays only see what's in range
+	r1.MaxDistance = 10.0
+	hits = RayCastAll(&r1, colliders, nil)
+	if len(hits) != 2 {
+		t.Errorf("RayCastAll() returned %d hits instead of 2 for a bounded ray.", len(hits))
+	}
+
+	// starting inside a collider reports a zero distance
+	r1.MaxDistance = 0.0
+	r1.Origin = mgl.Vec3{10.0, 0.0, 0.0}
+	hits = RayCastAll(&r1, colliders, nil)
+	if len(hits) != 2 || hits[0].Index != 3 || hits[0].Distance != 0.0 {
+		t.Errorf("RayCastAll() didn't return the containing box first with a zero distance: %v", hits)
+	}
+}
+
+func TestRayCastAllAllocs(t *testing.T) {
+	colliders := newTestRayCastColliders()
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+
+	hits := make([]RayHit, 0, len(colliders))
+	allocs := testing.AllocsPerRun(100, func() {
+		hits = RayCastAll(&r1, colliders, hits[:0])
+	})
+	if allocs != 0 || len(hits) != 3 {
+		t.Errorf("RayCastAll() allocated %v times with a buffer and returned %d hits", allocs, len(hits))
+	}
+}
+
+func TestRayCastAllOrder(t *testing.T) {
+	// boxes along the ray out of order, with every third one stacked
+	// above the one before it so that they're hit at the same distance
+	var colliders []Collider
+	for i := 0; i < 30; i++ {
+		x := float32((i*7)%10) * 3.0
+		y := float32(0.0)
+		if i%3 == 2 {
+			x = float32(((i-1)*7)%10) * 3.0
+			y = 0.5
+		}
+		colliders = append(colliders, newTestWorldBox(x+2.0, y, 0.0))
+	}
+
+	ray, _ := NewCollisionRay(mgl.Vec3{0, 0.25, 0}, mgl.Vec3{1, 0, 0})
+	hits := RayCastAll(ray, colliders, nil)
+	if len(hits) != len(colliders) {
+		t.Fatalf("RayCastAll() returned %d hits instead of %d", len(hits), len(colliders))
+	}
+	for i := 1; i < len(hits); i++ {
+		prev, hit := hits[i-1], hits[i]
+		if prev.Distance > hit.Distance || (prev.Distance == hit.Distance && prev.Index > hit.Index) {
+			t.Fatalf("RayCastAll() returned hits out of order at %d: %v then %v", i, prev, hit)
+		}
+	}
+}
+
+func TestRayCastFirst(t *testing.T) {
+	colliders := newTestRayCastColliders()
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+
+	hit, ok := RayCastFirst(&r1, colliders)
+	if !ok {
+		t.Fatal("RayCastFirst() didn't hit anything.")
+	}
+	if hit.Index != 1 || !mgl.FloatEqual(hit.Distance, 4.0) {
+		t.Errorf("RayCastFirst() returned the wrong hit: %d at %f", hit.Index, hit.Distance)
+	}
+	if r1.MaxDistance != 0.0 {
+		t.Error("RayCastFirst() modified the ray passed in.")
+	}
+
+	r1.SetDirection(mgl.Vec3{-1.0, 0.0, 0.0})
+	_, ok = RayCastFirst(&r1, colliders)
+	if ok {
+		t.Error("RayCastFirst() hit something with a ray pointed away from everything.")
+	}
+}
+
+func TestRayCastHitProperties(t *testing.T) {
+	colliders := newTestRayCastColliders()
+	near := colliders[1].(*Sphere)
+	near.UserData = "near sphere"
+	near.Material = Material{Friction: 0.5, Restitution: 0.25, SurfaceType: 3}
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+
+	hit, found := RayCastFirst(&r1, colliders)
+	if !found || hit.UserData != "near sphere" || hit.Material != near.Material {
+		t.Errorf("RayCastFirst() didn't return the UserData and Material of the hit: %v %v", hit.UserData, hit.Material)
+	}
+
+	hits := RayCastAll(&r1, colliders, nil)
+	if len(hits) < 2 || hits[0].UserData != "near sphere" || hits[1].UserData != nil {
+		t.Errorf("RayCastAll() didn't return the UserData of the hits: %v", hits)
+	}
+}
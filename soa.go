@@ -0,0 +1,249 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+	"math/bits"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// HitMask holds one bit per object tested by a batch function, set if that
+// object was hit. Bit i is bit i%64 of word i/64.
+type HitMask []uint64
+
+// newHitMask returns a zeroed mask with room for n bits, reusing the
+// memory of buf if it is large enough.
+func newHitMask(buf HitMask, n int) HitMask {
+	words := (n + 63) / 64
+	if cap(buf) < words {
+		return make(HitMask, words)
+	}
+	buf = buf[:words]
+	for i := range buf {
+		buf[i] = 0
+	}
+	return buf
+}
+
+// Get returns true if bit i is set.
+func (m HitMask) Get(i int) bool {
+	return m[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Count returns the number of bits that are set.
+func (m HitMask) Count() int {
+	count := 0
+	for _, word := range m {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// AABBoxSoA stores many axis aligned boxes as separate slices for each of
+// their world-space coordinates (a structure of arrays) so that one shape
+// can be tested against all of them in a tight loop. All of the slices
+// must be the same length; if they aren't, the batch tests hit no boxes.
+type AABBoxSoA struct {
+	MinX, MinY, MinZ []float32
+	MaxX, MaxY, MaxZ []float32
+}
+
+// NewAABBoxSoA creates a new, empty AABBoxSoA object with room for capacity
+// boxes before it has to grow.
+func NewAABBoxSoA(capacity int) *AABBoxSoA {
+	soa := new(AABBoxSoA)
+	soa.MinX = make([]float32, 0, capacity)
+	soa.MinY = make([]float32, 0, capacity)
+	soa.MinZ = make([]float32, 0, capacity)
+	soa.MaxX = make([]float32, 0, capacity)
+	soa.MaxY = make([]float32, 0, capacity)
+	soa.MaxZ = make([]float32, 0, capacity)
+	return soa
+}
+
+// Len returns the number of boxes.
+func (soa *AABBoxSoA) Len() int {
+	return len(soa.MinX)
+}
+
+// Add appends a box with the world-space corners and returns its index.
+func (soa *AABBoxSoA) Add(min, max mgl.Vec3) int {
+	soa.MinX = append(soa.MinX, min[0])
+	soa.MinY = append(soa.MinY, min[1])
+	soa.MinZ = append(soa.MinZ, min[2])
+	soa.MaxX = append(soa.MaxX, max[0])
+	soa.MaxY = append(soa.MaxY, max[1])
+	soa.MaxZ = append(soa.MaxZ, max[2])
+	return len(soa.MinX) - 1
+}
+
+// AddAABBox appends the box, with its offset applied, and returns its index.
+func (soa *AABBoxSoA) AddAABBox(b *AABBox) int {
+	return soa.Add(b.Min.Add(b.Offset), b.Max.Add(b.Offset))
+}
+
+// Set changes the world-space corners of the box at index i.
+func (soa *AABBoxSoA) Set(i int, min, max mgl.Vec3) {
+	soa.MinX[i], soa.MinY[i], soa.MinZ[i] = min[0], min[1], min[2]
+	soa.MaxX[i], soa.MaxY[i], soa.MaxZ[i] = max[0], max[1], max[2]
+}
+
+// Get returns the world-space corners of the box at index i.
+func (soa *AABBoxSoA) Get(i int) (mgl.Vec3, mgl.Vec3) {
+	return mgl.Vec3{soa.MinX[i], soa.MinY[i], soa.MinZ[i]}, mgl.Vec3{soa.MaxX[i], soa.MaxY[i], soa.MaxZ[i]}
+}
+
+// Clear removes all of the boxes but keeps their memory.
+func (soa *AABBoxSoA) Clear() {
+	soa.MinX, soa.MinY, soa.MinZ = soa.MinX[:0], soa.MinY[:0], soa.MinZ[:0]
+	soa.MaxX, soa.MaxY, soa.MaxZ = soa.MaxX[:0], soa.MaxY[:0], soa.MaxZ[:0]
+}
+
+// columns returns the coordinate slices cut to the number of boxes. The last
+// return value is false if they aren't all the same length, in which case
+// the batch functions below report that no boxes were hit.
+func (soa *AABBoxSoA) columns() (minX, minY, minZ, maxX, maxY, maxZ []float32, okay bool) {
+	n := len(soa.MinX)
+	if len(soa.MinY) != n || len(soa.MinZ) != n ||
+		len(soa.MaxX) != n || len(soa.MaxY) != n || len(soa.MaxZ) != n {
+		return nil, nil, nil, nil, nil, nil, false
+	}
+	return soa.MinX[:n], soa.MinY[:n], soa.MinZ[:n], soa.MaxX[:n], soa.MaxY[:n], soa.MaxZ[:n], true
+}
+
+// NOTE: the batch functions below check the coordinate slices once with
+// columns rather than on every box. Each result is turned into a bit with
+// boolBit, which the compiler may still compile to a branch.
+
+// CollideVsSphere tests the sphere against every box and returns a mask of
+// the boxes it touches. The mask reuses the memory of hits if it is large
+// enough so that nothing is allocated.
+func (soa *AABBoxSoA) CollideVsSphere(s *Sphere, hits HitMask) HitMask {
+	n := len(soa.MinX)
+	hits = newHitMask(hits, n)
+	minX, minY, minZ, maxX, maxY, maxZ, okay := soa.columns()
+	if !okay {
+		return hits
+	}
+
+	center := s.Center.Add(s.Offset)
+	cx, cy, cz := center[0], center[1], center[2]
+	rsq := s.Radius * s.Radius
+	for i := 0; i < n; i++ {
+		// only one of the two distances past a face can be positive
+		dx := clampPositive(minX[i]-cx) + clampPositive(cx-maxX[i])
+		dy := clampPositive(minY[i]-cy) + clampPositive(cy-maxY[i])
+		dz := clampPositive(minZ[i]-cz) + clampPositive(cz-maxZ[i])
+		hits[i/64] |= boolBit(dx*dx+dy*dy+dz*dz <= rsq) << uint(i%64)
+	}
+	return hits
+}
+
+// CollideVsAABBox tests the box against every box and returns a mask of
+// the boxes it touches. The mask reuses the memory of hits if it is large
+// enough so that nothing is allocated.
+func (soa *AABBoxSoA) CollideVsAABBox(b *AABBox, hits HitMask) HitMask {
+	n := len(soa.MinX)
+	hits = newHitMask(hits, n)
+	minX, minY, minZ, maxX, maxY, maxZ, okay := soa.columns()
+	if !okay {
+		return hits
+	}
+
+	bMin := b.Min.Add(b.Offset)
+	bMax := b.Max.Add(b.Offset)
+	for i := 0; i < n; i++ {
+		overlap := minX[i] <= bMax[0] && maxX[i] >= bMin[0] &&
+			minY[i] <= bMax[1] && maxY[i] >= bMin[1] &&
+			minZ[i] <= bMax[2] && maxZ[i] >= bMin[2]
+		hits[i/64] |= boolBit(overlap) << uint(i%64)
+	}
+	return hits
+}
+
+// CollideVsRay tests the ray against every box and returns a mask of the
+// boxes it hits, following the same rules as AABBox.CollideVsRay. The mask
+// reuses the memory of hits if it is large enough so that nothing is
+// allocated.
+func (soa *AABBoxSoA) CollideVsRay(ray *CollisionRay, hits HitMask) HitMask {
+	n := len(soa.MinX)
+	hits = newHitMask(hits, n)
+	if !ray.valid() {
+		return hits
+	}
+	minX, minY, minZ, maxX, maxY, maxZ, okay := soa.columns()
+	if !okay {
+		return hits
+	}
+
+	// slabs of axes the ray runs parallel to don't limit the distance but
+	// the origin has to be between them
+	ox, oy, oz := ray.Origin[0], ray.Origin[1], ray.Origin[2]
+	ix, iy, iz := ray.directionFraction[0], ray.directionFraction[1], ray.directionFraction[2]
+	px, py, pz := ray.direction[0] == 0, ray.direction[1] == 0, ray.direction[2] == 0
+	maxDist := ray.MaxDistance
+	negInf, posInf := float32(math.Inf(-1)), float32(math.Inf(1))
+	for i := 0; i < n; i++ {
+		tx1, tx2 := (minX[i]-ox)*ix, (maxX[i]-ox)*ix
+		ty1, ty2 := (minY[i]-oy)*iy, (maxY[i]-oy)*iy
+		tz1, tz2 := (minZ[i]-oz)*iz, (maxZ[i]-oz)*iz
+		if tx1 > tx2 {
+			tx1, tx2 = tx2, tx1
+		}
+		if ty1 > ty2 {
+			ty1, ty2 = ty2, ty1
+		}
+		if tz1 > tz2 {
+			tz1, tz2 = tz2, tz1
+		}
+		if px {
+			tx1, tx2 = negInf, posInf
+		}
+		if py {
+			ty1, ty2 = negInf, posInf
+		}
+		if pz {
+			tz1, tz2 = negInf, posInf
+		}
+		tmin := tx1
+		if ty1 > tmin {
+			tmin = ty1
+		}
+		if tz1 > tmin {
+			tmin = tz1
+		}
+		tmax := tx2
+		if ty2 < tmax {
+			tmax = ty2
+		}
+		if tz2 < tmax {
+			tmax = tz2
+		}
+
+		inside := (!px || (ox >= minX[i] && ox <= maxX[i])) &&
+			(!py || (oy >= minY[i] && oy <= maxY[i])) &&
+			(!pz || (oz >= minZ[i] && oz <= maxZ[i]))
+		hit := inside && tmax >= 0 && tmin <= tmax && (maxDist <= 0 || tmin <= maxDist)
+		hits[i/64] |= boolBit(hit) << uint(i%64)
+	}
+	return hits
+}
+
+// clampPositive returns x if it is positive and zero otherwise.
+func clampPositive(x float32) float32 {
+	if x > 0 {
+		return x
+	}
+	return 0
+}
+
+// boolBit returns 1 for true and 0 for false.
+func boolBit(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,138 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math/rand"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestSoABoxes makes a set of random boxes both as AABBox objects and
+// as an AABBoxSoA.
+func newTestSoABoxes(count int) ([]*AABBox, *AABBoxSoA) {
+	r := rand.New(rand.NewSource(42))
+	boxes := make([]*AABBox, count)
+	soa := NewAABBoxSoA(count)
+	for i := range boxes {
+		b := NewAABBox()
+		b.Min = mgl.Vec3{-r.Float32(), -r.Float32(), -r.Float32()}
+		b.Max = mgl.Vec3{r.Float32(), r.Float32(), r.Float32()}
+		b.SetOffset3f(r.Float32()*20-10, r.Float32()*20-10, r.Float32()*20-10)
+		boxes[i] = b
+		soa.AddAABBox(b)
+	}
+	return boxes, soa
+}
+
+func TestAABBoxSoAMatchesScalar(t *testing.T) {
+	boxes, soa := newTestSoABoxes(1000)
+	if soa.Len() != len(boxes) {
+		t.Fatalf("AABBoxSoA.Len() returned %d instead of %d", soa.Len(), len(boxes))
+	}
+
+	s := Sphere{Center: mgl.Vec3{1, 2, 3}, Radius: 4.0}
+	b := newTestWorldBox(-2, 0, 1)
+	b.Max = mgl.Vec3{3, 3, 3}
+	var rays []*CollisionRay
+	for _, d := range []mgl.Vec3{{1, 0, 0}, {0, -1, 0}, {1, 1, 1}, {-1, 0.5, 0}} {
+		ray, _ := NewCollisionRay(mgl.Vec3{0.3, 0.5, 0.25}, d)
+		rays = append(rays, ray)
+	}
+	rays[2].MaxDistance = 8.0
+
+	var hits HitMask
+	hits = soa.CollideVsSphere(&s, hits)
+	for i, box := range boxes {
+		if hits.Get(i) != (box.CollideVsSphere(&s) == Intersect) {
+			t.Errorf("AABBoxSoA.CollideVsSphere() disagreed with AABBox.CollideVsSphere() for box %d", i)
+		}
+	}
+	if hits.Count() == 0 {
+		t.Error("AABBoxSoA.CollideVsSphere() didn't hit any boxes.")
+	}
+
+	hits = soa.CollideVsAABBox(b, hits)
+	for i, box := range boxes {
+		if hits.Get(i) != (box.CollideVsAABBox(b) == Intersect) {
+			t.Errorf("AABBoxSoA.CollideVsAABBox() disagreed with AABBox.CollideVsAABBox() for box %d", i)
+		}
+	}
+
+	for r, ray := range rays {
+		hits = soa.CollideVsRay(ray, hits)
+		for i, box := range boxes {
+			result, _ := box.CollideVsRay(ray)
+			if hits.Get(i) != (result == Intersect) {
+				t.Errorf("AABBoxSoA.CollideVsRay() disagreed with AABBox.CollideVsRay() for ray %d and box %d", r, i)
+			}
+		}
+		if hits.Count() == 0 {
+			t.Errorf("AABBoxSoA.CollideVsRay() didn't hit any boxes with ray %d", r)
+		}
+	}
+}
+
+func TestAABBoxSoAMismatchedLengths(t *testing.T) {
+	_, soa := newTestSoABoxes(100)
+	soa.MaxY = soa.MaxY[:50]
+	s := Sphere{Center: mgl.Vec3{1, 2, 3}, Radius: 100.0}
+	b := newTestWorldBox(0, 0, 0)
+	b.Max = mgl.Vec3{100, 100, 100}
+	ray, _ := NewCollisionRay(mgl.Vec3{-12, 0, 0}, mgl.Vec3{1, 0, 0})
+
+	if hits := soa.CollideVsSphere(&s, nil); len(hits) != 2 || hits.Count() != 0 {
+		t.Errorf("AABBoxSoA.CollideVsSphere() returned %v for slices of different lengths", hits)
+	}
+	if hits := soa.CollideVsAABBox(b, nil); len(hits) != 2 || hits.Count() != 0 {
+		t.Errorf("AABBoxSoA.CollideVsAABBox() returned %v for slices of different lengths", hits)
+	}
+	if hits := soa.CollideVsRay(ray, nil); len(hits) != 2 || hits.Count() != 0 {
+		t.Errorf("AABBoxSoA.CollideVsRay() returned %v for slices of different lengths", hits)
+	}
+}
+
+func TestAABBoxSoAAllocs(t *testing.T) {
+	_, soa := newTestSoABoxes(1000)
+	s := Sphere{Center: mgl.Vec3{1, 2, 3}, Radius: 4.0}
+	b := newTestWorldBox(0, 0, 0)
+	ray, _ := NewCollisionRay(mgl.Vec3{-12, 0, 0}, mgl.Vec3{1, 0, 0})
+
+	hits := make(HitMask, 0, 16)
+	allocs := testing.AllocsPerRun(100, func() {
+		hits = soa.CollideVsSphere(&s, hits)
+		hits = soa.CollideVsAABBox(b, hits)
+		hits = soa.CollideVsRay(ray, hits)
+	})
+	if allocs != 0 {
+		t.Errorf("AABBoxSoA batch tests allocated %v times with a large enough mask", allocs)
+	}
+	if len(hits) != 16 {
+		t.Errorf("AABBoxSoA.CollideVsRay() returned a mask of %d words instead of 16", len(hits))
+	}
+}
+
+func BenchmarkAABBoxSoACollideVsSphere(b *testing.B) {
+	_, soa := newTestSoABoxes(4096)
+	s := Sphere{Center: mgl.Vec3{1, 2, 3}, Radius: 4.0}
+	var hits HitMask
+	for i := 0; i < b.N; i++ {
+		hits = soa.CollideVsSphere(&s, hits)
+	}
+}
+
+func BenchmarkAABBoxCollideVsSphere(b *testing.B) {
+	boxes, _ := newTestSoABoxes(4096)
+	s := Sphere{Center: mgl.Vec3{1, 2, 3}, Radius: 4.0}
+	colliders := make([]Collider, len(boxes))
+	for i, box := range boxes {
+		colliders[i] = box
+	}
+	for i := 0; i < b.N; i++ {
+		for _, c := range colliders {
+			c.CollideVsSphere(&s)
+		}
+	}
+}
@@ -4,6 +4,8 @@
 package glider
 
 import (
+	"math"
+
 	mgl "github.com/go-gl/mathgl/mgl32"
 )
 
@@ -18,6 +20,21 @@ type Sphere struct {
 
 	// Radius determines the size of the sphere
 	Radius float32
+
+	// Tags provides a way to label a sphere in a custom application
+	// (e.g. labelling a collision as "trigger" or "projectile").
+	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// sphere is hit, such as the game object that owns it.
+	UserData interface{}
+
+	// Material describes the surface of the sphere.
+	Material Material
 }
 
 // NewSphere creates a new Sphere object.
@@ -37,6 +54,23 @@ func (s1 *Sphere) SetOffset3f(x, y, z float32) {
 	s1.Offset[2] = z
 }
 
+// Validate checks the Sphere for non-finite values and a negative radius.
+func (s1 *Sphere) Validate() error {
+	if err := validateFinite("Sphere", "Center", s1.Center[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("Sphere", "Offset", s1.Offset[:]); err != nil {
+		return err
+	}
+	if !isFinite32(s1.Radius) {
+		return &ShapeError{Shape: "Sphere", Field: "Radius", Err: ErrNonFinite}
+	}
+	if s1.Radius < 0 {
+		return &ShapeError{Shape: "Sphere", Field: "Radius", Err: ErrNegativeRadius}
+	}
+	return nil
+}
+
 // CollideVsSphere tests a collision between two spheres.
 func (s1 *Sphere) CollideVsSphere(s2 *Sphere) int {
 	rSquared := s1.Radius + s2.Radius
@@ -62,9 +96,14 @@ func (s1 *Sphere) CollideVsAABBox(b *AABBox) int {
 	return b.CollideVsSphere(s1)
 }
 
-// CollideVsRay tests a collision between a sphere and a ray.
-// FIXME: sphere's don't return a distance at present
+// CollideVsRay tests a collision between a sphere and a ray and returns the
+// distance along the ray to the surface of the sphere. If the ray starts
+// inside the sphere the distance is zero. Invalid rays never intersect.
 func (s1 *Sphere) CollideVsRay(ray *CollisionRay) (int, float32) {
+	if !ray.valid() {
+		return NoIntersect, 0.0
+	}
+
 	var offsetSphere mgl.Vec3
 	offsetSphere = s1.Center.Add(s1.Offset)
 
@@ -88,11 +127,18 @@ func (s1 *Sphere) CollideVsRay(ray *CollisionRay) (int, float32) {
 	// we're behind the sphere, so test the length of the difference
 	// vs the radius of the sphere
 	vsq := ray.direction.Dot(ray.direction)
-	if vsq*wsq-proj*proj <= vsq*rsq {
-		return Intersect, 0.0
+	if vsq*wsq-proj*proj > vsq*rsq {
+		return NoIntersect, 0.0
+	}
+
+	// back up from the closest approach to where the ray enters the sphere
+	halfChord := float32(math.Sqrt(float64(rsq - (wsq - proj*proj))))
+	dist := proj - halfChord
+	if !ray.inRange(dist) {
+		return NoIntersect, dist
 	}
 
-	return NoIntersect, 0.0
+	return Intersect, dist
 }
 
 // CollideVsPlane tests a collision between a sphere and a plane.
@@ -107,3 +153,58 @@ func (s1 *Sphere) CollideVsPlane(p *Plane) int {
 
 	return Intersect
 }
+
+// ClassifyVsSphere returns Inside if the sphere is completely inside s2,
+// Outside if they don't touch and Intersecting otherwise.
+func (s1 *Sphere) ClassifyVsSphere(s2 *Sphere) Classification {
+	if s1.CollideVsSphere(s2) == NoIntersect {
+		return Outside
+	}
+
+	delta := s2.Center.Add(s2.Offset).Sub(s1.Center.Add(s1.Offset))
+	if s1.Radius <= s2.Radius && delta.Len()+s1.Radius <= s2.Radius {
+		return Inside
+	}
+	return Intersecting
+}
+
+// ClassifyVsAABBox returns Inside if the sphere is completely inside the
+// box, Outside if they don't touch and Intersecting otherwise.
+func (s1 *Sphere) ClassifyVsAABBox(b *AABBox) Classification {
+	if b.CollideVsSphere(s1) == NoIntersect {
+		return Outside
+	}
+
+	min := b.Min.Add(b.Offset)
+	max := b.Max.Add(b.Offset)
+	center := s1.Center.Add(s1.Offset)
+	for i := 0; i < 3; i++ {
+		if center[i]-s1.Radius < min[i] || center[i]+s1.Radius > max[i] {
+			return Intersecting
+		}
+	}
+	return Inside
+}
+
+// ClassifyVsPlane returns Front if the sphere is completely in front of the
+// plane, Back if it's completely behind it and Straddling otherwise.
+func (s1 *Sphere) ClassifyVsPlane(p *Plane) Classification {
+	return classifyExtentVsPlane(p.Distance(s1.Center.Add(s1.Offset)), s1.Radius)
+}
+
+// ClassifyVsFrustum returns Inside if the sphere is completely inside the
+// frustum, Outside if it's completely outside of one of the frustum's planes
+// and Intersecting otherwise.
+func (s1 *Sphere) ClassifyVsFrustum(f *Frustum) Classification {
+	return f.classify(s1.ClassifyVsPlane)
+}
+
+// support returns the point on the sphere furthest along d.
+func (s1 *Sphere) support(d mgl.Vec3) mgl.Vec3 {
+	center := s1.Center.Add(s1.Offset)
+	l := d.Len()
+	if l == 0 {
+		return center
+	}
+	return center.Add(d.Mul(s1.Radius / l))
+}
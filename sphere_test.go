@@ -188,3 +188,45 @@ func TestSphereCollisionVsPlane(t *testing.T) {
 		t.Errorf("Sphere.InstersectPlane() indicated a sphere didn't intersect that should have.")
 	}
 }
+
+func TestSphereCollisionVsRayDistance(t *testing.T) {
+	// Sphere {10, 0, 0} | r = 2.0
+	s1 := Sphere{Center: mgl.Vec3{10.0, 0.0, 0.0}, Radius: 2.0}
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+
+	intersect, dist := s1.CollideVsRay(&r1)
+	if intersect != Intersect {
+		t.Errorf("Sphere.IntersectRay() indicated a sphere didn't intersect that should have.")
+	}
+	if !mgl.FloatEqual(dist, 8.0) {
+		t.Errorf("Sphere.IntersectRay() returned the wrong distance: %f", dist)
+	}
+
+	// a segment that stops short of the sphere
+	r1.SetSegment(mgl.Vec3{0.0, 0.0, 0.0}, mgl.Vec3{7.0, 0.0, 0.0})
+	intersect, _ = s1.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Errorf("Sphere.IntersectRay() indicated a sphere intersected beyond the end of a segment.")
+	}
+
+	// a segment that reaches into the sphere
+	r1.SetSegment(mgl.Vec3{0.0, 0.0, 0.0}, mgl.Vec3{9.0, 0.0, 0.0})
+	intersect, _ = s1.CollideVsRay(&r1)
+	if intersect != Intersect {
+		t.Errorf("Sphere.IntersectRay() indicated a sphere didn't intersect a segment reaching into it.")
+	}
+}
+
+func TestSphereCollisionVsInvalidRay(t *testing.T) {
+	s1 := Sphere{Center: mgl.Vec3{10.0, 0.0, 0.0}, Radius: 2.0}
+
+	var r1 CollisionRay
+	r1.SetDirection(mgl.Vec3{0.0, 0.0, 0.0})
+	intersect, dist := s1.CollideVsRay(&r1)
+	if intersect != NoIntersect || dist != 0.0 {
+		t.Errorf("Sphere.IntersectRay() didn't reject a ray without a direction: %d, %f", intersect, dist)
+	}
+}
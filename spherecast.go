@@ -0,0 +1,207 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// sphereCastVsConvex sweeps a sphere of the radius along the ray against
+// a convex shape.
+func sphereCastVsConvex(ray *CollisionRay, radius float32, target convexShape) (int, CastHit) {
+	origin := ray.Origin
+	dir := ray.direction
+	cast := func(t float32) convexShape {
+		return pointShape(origin.Add(dir.Mul(t)))
+	}
+	return castConvex(ray, cast, radius, target)
+}
+
+// SphereCastVsAABBox sweeps a sphere of the radius centered on the ray's
+// origin along the ray and returns where it first touches the box.
+func SphereCastVsAABBox(ray *CollisionRay, radius float32, b *AABBox) (int, CastHit) {
+	return sphereCastVsConvex(ray, radius, b)
+}
+
+// SphereCastVsOBBox sweeps a sphere of the radius centered on the ray's
+// origin along the ray and returns where it first touches the box.
+func SphereCastVsOBBox(ray *CollisionRay, radius float32, obb *OBBox) (int, CastHit) {
+	return sphereCastVsConvex(ray, radius, obb)
+}
+
+// SphereCastVsSphere sweeps a sphere of the radius centered on the ray's
+// origin along the ray and returns where it first touches the sphere s.
+func SphereCastVsSphere(ray *CollisionRay, radius float32, s *Sphere) (int, CastHit) {
+	var hit CastHit
+	if !ray.valid() {
+		return NoIntersect, hit
+	}
+
+	// the same as a ray cast against a sphere with both radii
+	center := s.Center.Add(s.Offset)
+	total := radius + s.Radius
+	toOrigin := ray.Origin.Sub(center)
+	b := toOrigin.Dot(ray.direction)
+	c := toOrigin.Dot(toOrigin) - total*total
+	if c <= 0 {
+		hit.Normal = ray.direction.Mul(-1.0)
+		if l := toOrigin.Len(); l > 0 {
+			hit.Point = center.Add(toOrigin.Mul(s.Radius / l))
+		} else {
+			hit.Point = center
+		}
+		return Intersect, hit
+	}
+	disc := b*b - c
+	if b > 0 || disc < 0 {
+		return NoIntersect, hit
+	}
+
+	t := -b - float32(math.Sqrt(float64(disc)))
+	if !ray.inRange(t) {
+		return NoIntersect, hit
+	}
+	hit.Distance = t
+	hit.Normal = ray.Origin.Add(ray.direction.Mul(t)).Sub(center).Mul(1.0 / total)
+	hit.Point = center.Add(hit.Normal.Mul(s.Radius))
+	return Intersect, hit
+}
+
+// SphereCastVsPlane sweeps a sphere of the radius centered on the ray's
+// origin along the ray and returns where it first touches the plane. Like
+// Plane.CollideVsRay, the plane can be hit from either side.
+func SphereCastVsPlane(ray *CollisionRay, radius float32, p *Plane) (int, CastHit) {
+	return castVsPlane(ray, pointShape(ray.Origin), radius, p)
+}
+
+// SphereCastVsTriangles sweeps a sphere of the radius centered on the ray's
+// origin along the ray and returns where it first touches any of the
+// triangles, such as those of a Mesh or Heightfield. Triangles are treated
+// as two-sided. A radius of zero casts the ray against the triangles like
+// a zero radius sphere cast does against the other shapes.
+func SphereCastVsTriangles(ray *CollisionRay, radius float32, triangles TriangleSet) (int, CastHit) {
+	var hit CastHit
+	triCount := triangles.TriangleCount()
+	if !ray.valid() || radius < 0 || triCount == 0 {
+		return NoIntersect, hit
+	}
+	if radius == 0 {
+		return rayCastVsTriangles(ray, triangles)
+	}
+
+	// the sweep needs a finite distance, so unbounded rays stop once
+	// they are past all of the triangles
+	maxDist := ray.MaxDistance
+	if maxDist <= 0 {
+		for i := 0; i < triCount; i++ {
+			v0, v1, v2 := triangles.Triangle(i)
+			for _, v := range [3]mgl.Vec3{v0, v1, v2} {
+				maxDist = max32(maxDist, v.Sub(ray.Origin).Len())
+			}
+		}
+		maxDist += radius
+	}
+
+	// sweep a unit sphere in a space scaled down by the radius
+	scale := 1.0 / radius
+	base := ray.Origin.Mul(scale)
+	vel := ray.direction.Mul(maxDist * scale)
+	found := false
+	nearest := float32(1.0)
+	var nearestPoint mgl.Vec3
+	for i := 0; i < triCount; i++ {
+		v0, v1, v2 := triangles.Triangle(i)
+		touched, t, point := sweepUnitSphereVsTriangle(base, vel, v0.Mul(scale), v1.Mul(scale), v2.Mul(scale), nearest)
+		if touched {
+			found = true
+			nearest = t
+			nearestPoint = point
+		}
+	}
+	if !found {
+		return NoIntersect, hit
+	}
+
+	hit.Distance = nearest * maxDist
+	hit.Point = nearestPoint.Mul(radius)
+	center := ray.Origin.Add(ray.direction.Mul(hit.Distance))
+	if normal := center.Sub(hit.Point); normal.Len() > 0 && hit.Distance > 0 {
+		hit.Normal = normal.Normalize()
+	} else {
+		hit.Normal = ray.direction.Mul(-1.0)
+	}
+	return Intersect, hit
+}
+
+// rayCastVsTriangles casts the ray against each of the triangles and returns
+// the closest hit, with the normal of the triangle facing back at the ray.
+func rayCastVsTriangles(ray *CollisionRay, triangles TriangleSet) (int, CastHit) {
+	var hit CastHit
+	found := false
+	var n0, n1, n2 mgl.Vec3
+	for i := 0; i < triangles.TriangleCount(); i++ {
+		v0, v1, v2 := triangles.Triangle(i)
+		result, dist, _, _ := RayVsTriangle(ray, v0, v1, v2, false)
+		if result == Intersect && (!found || dist < hit.Distance) {
+			found = true
+			hit.Distance = dist
+			n0, n1, n2 = v0, v1, v2
+		}
+	}
+	if !found {
+		return NoIntersect, hit
+	}
+
+	hit.Point = ray.Origin.Add(ray.direction.Mul(hit.Distance))
+	hit.Normal = n1.Sub(n0).Cross(n2.Sub(n0)).Normalize()
+	if hit.Normal.Dot(ray.direction) > 0 {
+		hit.Normal = hit.Normal.Mul(-1.0)
+	}
+	return Intersect, hit
+}
+
+// SphereCast sweeps a sphere of the radius centered on the ray's origin
+// along the ray and returns where it first touches the collider. Boxes,
+// spheres, triangles, meshes, heightfields, voxel grids, ellipsoids,
+// cylinders and cones are supported; other colliders are never hit.
+func SphereCast(ray *CollisionRay, radius float32, c Collider) (int, CastHit) {
+	var result int
+	var hit CastHit
+	switch s := c.(type) {
+	case *AABBox:
+		result, hit = SphereCastVsAABBox(ray, radius, s)
+	case *Sphere:
+		result, hit = SphereCastVsSphere(ray, radius, s)
+	case *Triangle:
+		result, hit = SphereCastVsTriangles(ray, radius, singleTriangle{s})
+	case *Ellipsoid:
+		result, hit = sphereCastVsConvex(ray, radius, s)
+	case *Cylinder:
+		result, hit = sphereCastVsConvex(ray, radius, s)
+	case *Cone:
+		result, hit = sphereCastVsConvex(ray, radius, s)
+	case *VoxelGrid:
+		ball := &Sphere{Center: ray.Origin, Radius: radius}
+		r := mgl.Vec3{radius, radius, radius}
+		result, hit = castVsVoxels(ray, castAlong(ray, ball), Bounds{Min: r.Mul(-1.0), Max: r}, s)
+	case TriangleSet:
+		result, hit = SphereCastVsTriangles(ray, radius, s)
+	default:
+		return NoIntersect, hit
+	}
+	hit.Collider = c
+	return result, hit
+}
+
+// SphereCastFirst sweeps a sphere of the radius along the ray against all of
+// the colliders and returns the first one it touches. Colliders whose TagSet
+// doesn't pass all of the filters are skipped. The second return value is
+// false if nothing was hit.
+func SphereCastFirst(ray *CollisionRay, radius float32, colliders []Collider, filters ...TagFilter) (CastHit, bool) {
+	return castFirst(colliders, filters, func(c Collider) (int, CastHit) {
+		return SphereCast(ray, radius, c)
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSphereCastVsShapes(t *testing.T) {
+	// a sphere of radius 0.5 moving down the -z axis
+	ray, _ := NewCollisionRay(mgl.Vec3{0.0, 0.0, 5.0}, mgl.Vec3{0.0, 0.0, -1.0})
+	box := newTestWorldBox(0, 0, 0)
+	result, hit := SphereCastVsAABBox(ray, 0.5, box)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 4.0, 1e-3) {
+		t.Errorf("SphereCastVsAABBox() returned %v, %v instead of hitting at 4.0", result, hit.Distance)
+	}
+	if !hit.Normal.ApproxEqualThreshold(mgl.Vec3{0, 0, 1}, 1e-3) || !mgl.FloatEqualThreshold(hit.Point[2], 0.5, 1e-3) {
+		t.Errorf("SphereCastVsAABBox() returned the point %v and normal %v", hit.Point, hit.Normal)
+	}
+
+	// moving past the box with the sphere just clearing it
+	passing, _ := NewCollisionRay(mgl.Vec3{1.1, 0.0, 5.0}, mgl.Vec3{0.0, 0.0, -1.0})
+	if result, _ = SphereCastVsAABBox(passing, 0.5, box); result != NoIntersect {
+		t.Error("SphereCastVsAABBox() hit a box the sphere should have passed by.")
+	}
+	ray.MaxDistance = 3.0
+	if result, _ = SphereCastVsAABBox(ray, 0.5, box); result != NoIntersect {
+		t.Error("SphereCastVsAABBox() hit a box past the ray's max distance.")
+	}
+	ray.MaxDistance = 0.0
+
+	obb := NewOBBox()
+	obb.HalfSize = mgl.Vec3{0.5, 0.5, 0.5}
+	obb.SetOrientation(mgl.QuatRotate(mgl.DegToRad(45.0), mgl.Vec3{0, 1, 0}))
+	result, hit = SphereCastVsOBBox(ray, 0.5, obb)
+	corner := float32(0.7071068)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 5.0-corner-0.5, 1e-3) {
+		t.Errorf("SphereCastVsOBBox() returned %v, %v instead of hitting the rotated corner", result, hit.Distance)
+	}
+
+	s := Sphere{Center: mgl.Vec3{0.0, 0.0, 0.0}, Radius: 1.0}
+	result, hit = SphereCastVsSphere(ray, 0.5, &s)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 3.5, 1e-4) {
+		t.Errorf("SphereCastVsSphere() returned %v, %v instead of hitting at 3.5", result, hit.Distance)
+	}
+	if !hit.Point.ApproxEqualThreshold(mgl.Vec3{0, 0, 1}, 1e-4) || !hit.Normal.ApproxEqualThreshold(mgl.Vec3{0, 0, 1}, 1e-4) {
+		t.Errorf("SphereCastVsSphere() returned the point %v and normal %v", hit.Point, hit.Normal)
+	}
+	if result, _ = SphereCastVsSphere(passing, 0.5, &s); result != Intersect {
+		t.Error("SphereCastVsSphere() missed a sphere the cast should have grazed.")
+	}
+
+	plane := NewPlaneFromNormalAndPoint(mgl.Vec3{0, 0, 1}, mgl.Vec3{0, 0, 0})
+	result, hit = SphereCastVsPlane(ray, 0.5, plane)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 4.5, 1e-4) || !hit.Point.ApproxEqualThreshold(mgl.Vec3{}, 1e-4) {
+		t.Errorf("SphereCastVsPlane() returned %v, %v at %v instead of hitting at 4.5", result, hit.Distance, hit.Point)
+	}
+	away, _ := NewCollisionRay(mgl.Vec3{0.0, 0.0, 5.0}, mgl.Vec3{1.0, 0.0, 1.0})
+	if result, _ = SphereCastVsPlane(away, 0.5, plane); result != NoIntersect {
+		t.Error("SphereCastVsPlane() hit a plane the sphere was moving away from.")
+	}
+	touching, _ := NewCollisionRay(mgl.Vec3{0.0, 0.0, -0.25}, mgl.Vec3{1.0, 0.0, 0.0})
+	if result, hit = SphereCastVsPlane(touching, 0.5, plane); result != Intersect || hit.Distance != 0 {
+		t.Errorf("SphereCastVsPlane() returned %v, %v for a sphere starting on the plane", result, hit.Distance)
+	}
+}
+
+func TestSphereCastVsMesh(t *testing.T) {
+	// a floor made of two triangles
+	vertices := []mgl.Vec3{
+		{-5.0, 0.0, -5.0}, {5.0, 0.0, -5.0}, {5.0, 0.0, 5.0}, {-5.0, 0.0, 5.0},
+	}
+	floor := NewMesh(vertices, []uint32{0, 2, 1, 0, 3, 2})
+
+	ray, _ := NewCollisionRay(mgl.Vec3{1.0, 4.0, 1.0}, mgl.Vec3{0.0, -1.0, 0.0})
+	result, hit := SphereCast(ray, 0.5, floor)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 3.5, 1e-3) {
+		t.Errorf("SphereCast() returned %v, %v instead of hitting the floor at 3.5", result, hit.Distance)
+	}
+	if !hit.Point.ApproxEqualThreshold(mgl.Vec3{1, 0, 1}, 1e-3) || !hit.Normal.ApproxEqualThreshold(mgl.Vec3{0, 1, 0}, 1e-3) {
+		t.Errorf("SphereCast() returned the point %v and normal %v for the floor", hit.Point, hit.Normal)
+	}
+	if hit.Collider != floor {
+		t.Error("SphereCast() didn't set the collider that was hit.")
+	}
+
+	// sliding along just above the floor and then clipping its edge
+	edge, _ := NewCollisionRay(mgl.Vec3{-8.0, -0.4, 0.0}, mgl.Vec3{1.0, 0.0, 0.0})
+	result, hit = SphereCast(edge, 0.5, floor)
+	if result != Intersect || hit.Distance <= 2.5 || hit.Distance >= 3.0 {
+		t.Errorf("SphereCast() returned %v, %v instead of clipping the floor's edge", result, hit.Distance)
+	}
+
+	ray.MaxDistance = 3.0
+	if result, _ = SphereCast(ray, 0.5, floor); result != NoIntersect {
+		t.Error("SphereCast() hit a floor past the ray's max distance.")
+	}
+}
+
+func TestSphereCastVsVoxelsAndZeroRadius(t *testing.T) {
+	vg := newTestVoxelFloor()
+	ray, _ := NewCollisionRay(mgl.Vec3{2.5, 5.0, 2.5}, mgl.Vec3{0.0, -1.0, 0.0})
+	result, hit := SphereCast(ray, 0.5, vg)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 3.5, 1e-3) || hit.Collider != vg {
+		t.Errorf("SphereCast() returned %v, %v instead of hitting the voxel floor at 3.5", result, hit.Distance)
+	}
+	if hit, okay := SphereCastFirst(ray, 0.5, []Collider{vg}); !okay || !mgl.FloatEqualThreshold(hit.Distance, 3.5, 1e-3) {
+		t.Errorf("SphereCastFirst() returned %v, %v instead of hitting the voxel floor", hit, okay)
+	}
+
+	// a zero radius sweep hits a mesh like a ray does
+	vertices := []mgl.Vec3{
+		{-5.0, 0.0, -5.0}, {5.0, 0.0, -5.0}, {5.0, 0.0, 5.0}, {-5.0, 0.0, 5.0},
+	}
+	floor := NewMesh(vertices, []uint32{0, 2, 1, 0, 3, 2})
+	ray, _ = NewCollisionRay(mgl.Vec3{1.0, 4.0, 1.0}, mgl.Vec3{0.0, -1.0, 0.0})
+	result, hit = SphereCast(ray, 0.0, floor)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 4.0, 1e-4) {
+		t.Errorf("SphereCast() returned %v, %v for a zero radius instead of hitting the floor at 4", result, hit.Distance)
+	}
+	if hit.Point.Sub(mgl.Vec3{1, 0, 1}).Len() > 1e-4 || hit.Normal.Sub(mgl.Vec3{0, 1, 0}).Len() > 1e-4 {
+		t.Errorf("SphereCast() returned the point %v and normal %v for a zero radius", hit.Point, hit.Normal)
+	}
+	ray.MaxDistance = 3.0
+	if result, _ = SphereCast(ray, 0.0, floor); result != NoIntersect {
+		t.Error("SphereCast() hit a floor past the ray's max distance with a zero radius.")
+	}
+}
+
+func TestWorldSphereCast(t *testing.T) {
+	w := NewWorld(NewSweepAndPruneBroadphase())
+	near := w.Add(newTestWorldBox(0, 0, -5))
+	w.Add(newTestWorldBox(0, 0, -10))
+	w.Add(newTestWorldBox(1.5, 0, -2))
+
+	ray, _ := NewCollisionRay(mgl.Vec3{}, mgl.Vec3{0, 0, -1})
+	hit, okay := w.SphereCast(ray, 0.5, AllLayers)
+	if !okay || hit.Handle != near || !mgl.FloatEqualThreshold(hit.Distance, 4.0, 1e-3) {
+		t.Errorf("World.SphereCast() returned %v, %v instead of the nearest box", hit, okay)
+	}
+
+	// a wider sphere clips the box off to the side first
+	hit, okay = w.SphereCast(ray, 1.2, AllLayers)
+	if !okay || hit.Handle == near || !mgl.FloatEqualThreshold(hit.Distance, 0.8367, 1e-3) {
+		t.Errorf("World.SphereCast() returned %v, %v instead of the box off to the side", hit, okay)
+	}
+
+	ray.MaxDistance = 2.0
+	if _, okay = w.SphereCast(ray, 0.5, AllLayers); okay {
+		t.Error("World.SphereCast() hit a box past the ray's max distance.")
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+// TagSet is a set of up to 64 tags stored as a bitset so that testing for
+// tags is a single operation instead of comparing strings. The bits are
+// handed out by a TagRegistry.
+type TagSet uint64
+
+// MaxTags is the most tags a TagRegistry can hold.
+const MaxTags = 64
+
+// HasTag returns true if the set holds the tag.
+func (ts TagSet) HasTag(tag TagSet) bool {
+	return tag != 0 && ts&tag == tag
+}
+
+// HasAny returns true if the set holds any of the tags in other.
+func (ts TagSet) HasAny(other TagSet) bool {
+	return ts&other != 0
+}
+
+// HasAll returns true if the set holds all of the tags in other.
+func (ts TagSet) HasAll(other TagSet) bool {
+	return ts&other == other
+}
+
+// With returns the set with the tags in other added.
+func (ts TagSet) With(other TagSet) TagSet {
+	return ts | other
+}
+
+// Without returns the set with the tags in other removed.
+func (ts TagSet) Without(other TagSet) TagSet {
+	return ts &^ other
+}
+
+// TagRegistry interns tag names into the bits of a TagSet. Every name gets
+// its own bit the first time it is seen, so the same registry needs to be
+// used for all of the shapes that are compared.
+type TagRegistry struct {
+	bits  map[string]TagSet
+	names []string
+}
+
+// NewTagRegistry creates a new, empty TagRegistry object.
+func NewTagRegistry() *TagRegistry {
+	r := new(TagRegistry)
+	r.bits = make(map[string]TagSet)
+	return r
+}
+
+// Tag returns the TagSet holding only the named tag, registering the name
+// if it hasn't been seen before. ErrTooManyTags is returned if the registry
+// is already full.
+func (r *TagRegistry) Tag(name string) (TagSet, error) {
+	if tag, okay := r.bits[name]; okay {
+		return tag, nil
+	}
+	if len(r.names) >= MaxTags {
+		return 0, ErrTooManyTags
+	}
+	tag := TagSet(1) << uint(len(r.names))
+	r.bits[name] = tag
+	r.names = append(r.names, name)
+	return tag, nil
+}
+
+// Set returns the TagSet holding all of the named tags, registering any
+// names that haven't been seen before. It can be used to build a shape's
+// TagSet from its Tags.
+func (r *TagRegistry) Set(names ...string) (TagSet, error) {
+	var ts TagSet
+	for _, name := range names {
+		tag, err := r.Tag(name)
+		if err != nil {
+			return ts, err
+		}
+		ts |= tag
+	}
+	return ts, nil
+}
+
+// Lookup returns the TagSet for the named tag without registering it. The
+// second return value is false if the name hasn't been registered.
+func (r *TagRegistry) Lookup(name string) (TagSet, bool) {
+	tag, okay := r.bits[name]
+	return tag, okay
+}
+
+// Names returns the names of all of the tags in the set in the order they
+// were registered.
+func (r *TagRegistry) Names(ts TagSet) []string {
+	var names []string
+	for i, name := range r.names {
+		if ts&(TagSet(1)<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// TagFilter decides whether a collider with the given tags should be
+// included in the results of a query.
+type TagFilter func(ts TagSet) bool
+
+// WithAllTags returns a TagFilter that only passes colliders with all of
+// the tags.
+func WithAllTags(tags TagSet) TagFilter {
+	return func(ts TagSet) bool { return ts.HasAll(tags) }
+}
+
+// WithAnyTag returns a TagFilter that only passes colliders with at least
+// one of the tags.
+func WithAnyTag(tags TagSet) TagFilter {
+	return func(ts TagSet) bool { return ts.HasAny(tags) }
+}
+
+// WithoutTags returns a TagFilter that only passes colliders with none of
+// the tags.
+func WithoutTags(tags TagSet) TagFilter {
+	return func(ts TagSet) bool { return !ts.HasAny(tags) }
+}
+
+// passesTagFilters returns true if the collider passes all of the filters.
+func passesTagFilters(c Collider, filters []TagFilter) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	ts := colliderProperties(c).tagSet
+	for _, filter := range filters {
+		if !filter(ts) {
+			return false
+		}
+	}
+	return true
+}
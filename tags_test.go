@@ -0,0 +1,98 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestTagRegistry(t *testing.T) {
+	r := NewTagRegistry()
+	wall, _ := r.Tag("wall")
+	floor, _ := r.Tag("floor")
+	if wall == floor || wall == 0 || floor == 0 {
+		t.Fatalf("TagRegistry.Tag() didn't give different tags their own bit: %b %b", wall, floor)
+	}
+	if again, _ := r.Tag("wall"); again != wall {
+		t.Error("TagRegistry.Tag() gave the same name a different bit.")
+	}
+
+	ts, _ := r.Set("wall", "metal")
+	metal, okay := r.Lookup("metal")
+	if !okay || !ts.HasTag(wall) || !ts.HasTag(metal) || ts.HasTag(floor) {
+		t.Errorf("TagRegistry.Set() returned the wrong set: %b", ts)
+	}
+	if !ts.HasAny(floor|metal) || ts.HasAll(floor|metal) || !ts.HasAll(wall|metal) {
+		t.Error("TagSet.HasAny() or TagSet.HasAll() returned the wrong result.")
+	}
+	if names := r.Names(ts); len(names) != 2 || names[0] != "wall" || names[1] != "metal" {
+		t.Errorf("TagRegistry.Names() returned %v", names)
+	}
+	if ts.Without(metal).With(floor) != wall|floor {
+		t.Error("TagSet.With() or TagSet.Without() returned the wrong set.")
+	}
+
+	if _, okay := r.Lookup("water"); okay {
+		t.Error("TagRegistry.Lookup() found a name that wasn't registered.")
+	}
+
+	// fill up the registry
+	for i := 0; i < MaxTags; i++ {
+		r.Tag(fmt.Sprintf("tag%d", i))
+	}
+	if _, err := r.Tag("one too many"); !errors.Is(err, ErrTooManyTags) {
+		t.Errorf("TagRegistry.Tag() didn't return ErrTooManyTags when full: %v", err)
+	}
+}
+
+func TestTagFilteredQueries(t *testing.T) {
+	r := NewTagRegistry()
+	enemy, _ := r.Tag("enemy")
+	glass, _ := r.Tag("glass")
+
+	colliders := newTestRayCastColliders()
+	colliders[1].(*Sphere).TagSet = glass
+	colliders[3].(*AABBox).TagSet = enemy
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.0, 0.0, 0.0}
+	r1.SetDirection(mgl.Vec3{1.0, 0.0, 0.0})
+
+	// shoot through the glass
+	hit, found := RayCastFirst(&r1, colliders, WithoutTags(glass))
+	if !found || hit.Index != 3 {
+		t.Errorf("RayCastFirst() didn't skip the glass sphere: %v %v", hit, found)
+	}
+	if hits := RayCastAll(&r1, colliders, nil, WithAnyTag(enemy|glass)); len(hits) != 2 {
+		t.Errorf("RayCastAll() returned %d hits instead of 2 tagged ones", len(hits))
+	}
+
+	w := NewWorld(nil)
+	var handles []Handle
+	for _, c := range colliders {
+		handles = append(handles, w.Add(c))
+	}
+	hit, found = w.RayCast(&r1, AllLayers, WithAllTags(enemy))
+	if !found || hit.Handle != handles[3] {
+		t.Errorf("World.RayCast() didn't only hit the enemy: %v %v", hit, found)
+	}
+	if points := w.QueryPoint(mgl.Vec3{5, 0, 0}, AllLayers, nil, WithAllTags(enemy)); len(points) != 0 {
+		t.Errorf("World.QueryPoint() returned %v for a point outside the enemy", points)
+	}
+}
+
+func TestColliderTags(t *testing.T) {
+	e := NewEllipsoid()
+	e.Tags = []string{"player", "blue"}
+	if tags := ColliderTags(e); len(tags) != 2 || tags[0] != "player" || tags[1] != "blue" {
+		t.Errorf("ColliderTags() returned %v instead of the ellipsoid's tags", tags)
+	}
+	if tags := ColliderTags(NewSphere()); len(tags) != 0 {
+		t.Errorf("ColliderTags() returned %v for a sphere without tags", tags)
+	}
+}
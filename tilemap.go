@@ -0,0 +1,456 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// TileKind describes how a tile in a TileMap collides.
+type TileKind uint8
+
+const (
+	// TileEmpty tiles never collide.
+	TileEmpty TileKind = iota
+
+	// TileSolid tiles block movement and rays from every side.
+	TileSolid
+
+	// TileOneWay tiles are platforms that only block movement and rays
+	// coming down onto their top face from above.
+	TileOneWay
+)
+
+// TileMap is a 2d collision shape made from a grid of equally sized square
+// tiles, such as the level of a platformer. The Y axis points up, so tile
+// (0, 0) is the lower-left tile of the map.
+type TileMap struct {
+	// Tiles holds Width * Height tiles in rows along the X axis, so the tile
+	// at (x, y) is Tiles[y*Width+x].
+	Tiles []TileKind
+
+	// Width is the number of tiles along the X axis.
+	Width int
+
+	// Height is the number of tiles along the Y axis.
+	Height int
+
+	// TileSize is the length of the sides of each tile.
+	TileSize float32
+
+	// Offset is the world-space location of the lower-left corner of the map
+	Offset mgl.Vec2
+
+	// Tags provides a way to label a tile map in a custom application
+	// (e.g. labelling a collision as "level" or "background").
+	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// tile map is hit, such as the level it was loaded from.
+	UserData interface{}
+
+	// Material describes the surface of the tile map.
+	Material Material
+}
+
+// TileHit describes where a ray cast hit a TileMap.
+type TileHit struct {
+	// Distance is the distance along the ray to where it enters the tile.
+	Distance float32
+
+	// Tile is the X and Y coordinate of the tile that was hit.
+	Tile [2]int
+
+	// Normal is the normal of the tile edge the ray entered through. Rays
+	// that start inside a solid tile get a normal facing back along the ray.
+	Normal mgl.Vec2
+
+	// UserData is the UserData of the tile map.
+	UserData interface{}
+
+	// Material is the Material of the tile map.
+	Material Material
+}
+
+// TileMove is the result of moving an AABSquare through a TileMap.
+type TileMove struct {
+	// Delta is how far the square can move without entering a tile.
+	Delta mgl.Vec2
+
+	// Correction is how much was removed from the requested movement on each
+	// axis to keep the square out of the tiles. Adding it to the requested
+	// movement gives Delta.
+	Correction mgl.Vec2
+
+	// HitX is true if movement along the X axis was blocked by a tile.
+	HitX bool
+
+	// HitY is true if movement along the Y axis was blocked by a tile.
+	HitY bool
+}
+
+// NewTileMap creates a new, empty TileMap object with the given number of
+// tiles on each axis.
+func NewTileMap(width, height int, tileSize float32) *TileMap {
+	tm := new(TileMap)
+	tm.Width = width
+	tm.Height = height
+	tm.TileSize = tileSize
+	if width > 0 && height > 0 {
+		tm.Tiles = make([]TileKind, width*height)
+	}
+	return tm
+}
+
+// SetOffset changes the offset of the collision object.
+func (tm *TileMap) SetOffset(offset *mgl.Vec2) {
+	tm.Offset = *offset
+}
+
+// SetOffset2f changes the offset of the collision object.
+func (tm *TileMap) SetOffset2f(x, y float32) {
+	tm.Offset[0] = x
+	tm.Offset[1] = y
+}
+
+// Validate checks the TileMap for non-finite values, a map without any
+// tiles or a tile count that doesn't match the map and a tile size that
+// isn't positive.
+func (tm *TileMap) Validate() error {
+	if tm.Width <= 0 || tm.Height <= 0 || len(tm.Tiles) != tm.Width*tm.Height {
+		return &ShapeError{Shape: "TileMap", Field: "Tiles", Err: ErrInvalidDimensions}
+	}
+	if err := validateFinite("TileMap", "Offset", tm.Offset[:]); err != nil {
+		return err
+	}
+	if !isFinite32(tm.TileSize) {
+		return &ShapeError{Shape: "TileMap", Field: "TileSize", Err: ErrNonFinite}
+	}
+	if tm.TileSize <= 0 {
+		return &ShapeError{Shape: "TileMap", Field: "TileSize", Err: ErrNegativeSize}
+	}
+	return nil
+}
+
+// InBounds returns true if the tile coordinate is inside the map.
+func (tm *TileMap) InBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < tm.Width && y < tm.Height
+}
+
+// Set changes the kind of the tile at the coordinate. Coordinates outside
+// of the map are ignored.
+func (tm *TileMap) Set(x, y int, kind TileKind) {
+	if tm.InBounds(x, y) {
+		tm.Tiles[y*tm.Width+x] = kind
+	}
+}
+
+// Get returns the kind of the tile at the coordinate. Coordinates outside
+// of the map are always TileEmpty.
+func (tm *TileMap) Get(x, y int) TileKind {
+	if !tm.InBounds(x, y) {
+		return TileEmpty
+	}
+	return tm.Tiles[y*tm.Width+x]
+}
+
+// TileAt returns the coordinate of the tile under the world-space point.
+// The last return value is false if the point is outside of the map.
+func (tm *TileMap) TileAt(p mgl.Vec2) (int, int, bool) {
+	if tm.TileSize <= 0 {
+		return 0, 0, false
+	}
+	x := int(math.Floor(float64((p[0] - tm.Offset[0]) / tm.TileSize)))
+	y := int(math.Floor(float64((p[1] - tm.Offset[1]) / tm.TileSize)))
+	return x, y, tm.InBounds(x, y)
+}
+
+// tileSpan returns the range of tiles, inclusive, that the local-space span
+// from min to max overlaps on one axis. Spans that only touch the edge of
+// a tile don't overlap it.
+func (tm *TileMap) tileSpan(min, max float32) (int, int) {
+	first := int(math.Floor(float64(min / tm.TileSize)))
+	last := int(math.Ceil(float64(max/tm.TileSize))) - 1
+	return first, last
+}
+
+// CollideVsAABSquare tests a collision between a tile map and an AABSquare.
+// Only solid tiles that the square overlaps count; one-way tiles never
+// collide with a square that isn't moving.
+func (tm *TileMap) CollideVsAABSquare(b *AABSquare) int {
+	if tm.TileSize <= 0 {
+		return NoIntersect
+	}
+	min := b.Min.Add(b.Offset).Sub(tm.Offset)
+	max := b.Max.Add(b.Offset).Sub(tm.Offset)
+	x0, x1 := tm.tileSpan(min[0], max[0])
+	y0, y1 := tm.tileSpan(min[1], max[1])
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			if tm.Get(x, y) == TileSolid {
+				return Intersect
+			}
+		}
+	}
+	return NoIntersect
+}
+
+// CollideVsAABSquareWithMTV tests a collision between a tile map and an
+// AABSquare like CollideVsAABSquare and also returns the minimum translation
+// vector, per axis, that pushes the square back out of the solid tiles it
+// overlaps. Each tile pushes the square out through whichever of its faces
+// is closest, skipping faces that another solid tile covers so that a square
+// sunk into a floor or wall isn't snagged on the seams between its tiles.
+// The square itself isn't moved; add the returned vector to its Offset.
+func (tm *TileMap) CollideVsAABSquareWithMTV(b *AABSquare) (int, mgl.Vec2) {
+	var mtv mgl.Vec2
+	if tm.TileSize <= 0 {
+		return NoIntersect, mtv
+	}
+	min := b.Min.Add(b.Offset).Sub(tm.Offset)
+	max := b.Max.Add(b.Offset).Sub(tm.Offset)
+	x0, x1 := tm.tileSpan(min[0], max[0])
+	y0, y1 := tm.tileSpan(min[1], max[1])
+
+	// faces against another solid tile or the edge of the map are covered
+	covered := func(x, y int) bool {
+		return !tm.InBounds(x, y) || tm.Get(x, y) == TileSolid
+	}
+
+	result := NoIntersect
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			if tm.Get(x, y) != TileSolid {
+				continue
+			}
+			result = Intersect
+
+			tile := [2]int{x, y}
+			tileMin := mgl.Vec2{float32(x), float32(y)}.Mul(tm.TileSize)
+			var push mgl.Vec2
+			var open [2]bool
+			for axis := 0; axis < 2; axis++ {
+				prev, next := tile, tile
+				prev[axis]--
+				next[axis]++
+				down := tileMin[axis] - max[axis]
+				up := tileMin[axis] + tm.TileSize - min[axis]
+				downOpen := !covered(prev[0], prev[1])
+				upOpen := !covered(next[0], next[1])
+				if downOpen && (!upOpen || -down <= up) {
+					push[axis], open[axis] = down, true
+				} else if upOpen {
+					push[axis], open[axis] = up, true
+				}
+			}
+
+			// push along the axis that needs the smaller push
+			axis := 0
+			if !open[0] || (open[1] && fabs32(push[1]) < fabs32(push[0])) {
+				axis = 1
+			}
+			if open[axis] && fabs32(push[axis]) > fabs32(mtv[axis]) {
+				mtv[axis] = push[axis]
+			}
+		}
+	}
+	return result, mtv
+}
+
+// MoveAABSquare works out how far the square can move by delta before it
+// runs into the tiles, moving along the X axis first and then the Y axis
+// so that a square sliding along the floor or a wall isn't stopped by it.
+// The square itself isn't moved; add the returned Delta to its Offset.
+func (tm *TileMap) MoveAABSquare(b *AABSquare, delta mgl.Vec2) TileMove {
+	var move TileMove
+	if tm.TileSize <= 0 {
+		move.Delta = delta
+		return move
+	}
+
+	min := b.Min.Add(b.Offset).Sub(tm.Offset)
+	max := b.Max.Add(b.Offset).Sub(tm.Offset)
+	for axis := 0; axis < 2; axis++ {
+		allowed, hit := tm.sweepAxis(min, max, axis, delta[axis])
+		move.Delta[axis] = allowed
+		move.Correction[axis] = allowed - delta[axis]
+		min[axis] += allowed
+		max[axis] += allowed
+		if axis == 0 {
+			move.HitX = hit
+		} else {
+			move.HitY = hit
+		}
+	}
+	return move
+}
+
+// sweepAxis moves the local-space bounds along one axis and returns how far
+// they can go before entering a tile that blocks them. Tiles the bounds
+// already overlap are ignored so that a square stuck in a wall can still
+// move out of it.
+func (tm *TileMap) sweepAxis(min, max mgl.Vec2, axis int, d float32) (float32, bool) {
+	if d == 0 {
+		return 0, false
+	}
+
+	other := 1 - axis
+	o0, o1 := tm.tileSpan(min[other], max[other])
+	tile := [2]int{}
+
+	blocks := func(kind TileKind) bool {
+		if kind == TileSolid {
+			return true
+		}
+		// one-way platforms only stop things falling onto them
+		return kind == TileOneWay && axis == 1 && d < 0
+	}
+
+	if d > 0 {
+		first := int(math.Floor(float64(max[axis] / tm.TileSize)))
+		last := int(math.Ceil(float64((max[axis]+d)/tm.TileSize))) - 1
+		for i := first; i <= last; i++ {
+			allowed := float32(i)*tm.TileSize - max[axis]
+			if allowed < 0 {
+				continue
+			}
+			for j := o0; j <= o1; j++ {
+				tile[axis], tile[other] = i, j
+				if blocks(tm.Get(tile[0], tile[1])) {
+					return allowed, true
+				}
+			}
+		}
+		return d, false
+	}
+
+	first := int(math.Ceil(float64(min[axis]/tm.TileSize))) - 1
+	last := int(math.Floor(float64((min[axis] + d) / tm.TileSize)))
+	for i := first; i >= last; i-- {
+		allowed := float32(i+1)*tm.TileSize - min[axis]
+		if allowed > 0 {
+			continue
+		}
+		for j := o0; j <= o1; j++ {
+			tile[axis], tile[other] = i, j
+			if blocks(tm.Get(tile[0], tile[1])) {
+				return allowed, true
+			}
+		}
+	}
+	return d, false
+}
+
+// CollideVsRay tests to see if a raycast hits a tile and returns the
+// distance to the first one hit.
+func (tm *TileMap) CollideVsRay(ray *CollisionRay2D) (int, float32) {
+	result, hit := tm.CollideVsRayDetailed(ray)
+	return result, hit.Distance
+}
+
+// CollideVsRayDetailed tests to see if a raycast hits a tile and returns
+// the distance, tile coordinate and edge normal of the first one hit. Tiles
+// are visited in the order the ray passes through them. One-way tiles are
+// only hit when the ray comes down through their top edge.
+func (tm *TileMap) CollideVsRayDetailed(ray *CollisionRay2D) (int, TileHit) {
+	if !ray.valid() || tm.TileSize <= 0 || tm.Width <= 0 || tm.Height <= 0 {
+		return NoIntersect, TileHit{}
+	}
+
+	// work in the map's local space
+	o := ray.Origin.Sub(tm.Offset)
+	d := ray.direction
+	dims := [2]int{tm.Width, tm.Height}
+
+	// clip the ray to the map's bounds, remembering which edge it entered
+	tmin := float32(0.0)
+	tmax := float32(math.Inf(1))
+	entryAxis := -1
+	for i := 0; i < 2; i++ {
+		size := float32(dims[i]) * tm.TileSize
+		if d[i] == 0 {
+			if o[i] < 0 || o[i] > size {
+				return NoIntersect, TileHit{}
+			}
+			continue
+		}
+		t1 := (0 - o[i]) / d[i]
+		t2 := (size - o[i]) / d[i]
+		near := min32(t1, t2)
+		if near > tmin {
+			tmin = near
+			entryAxis = i
+		}
+		tmax = min32(tmax, max32(t1, t2))
+	}
+	if tmin > tmax || !ray.inRange(tmin) {
+		return NoIntersect, TileHit{}
+	}
+
+	// set up the walk from the tile where the ray enters the map
+	var cell, step [2]int
+	var tNext, tDelta [2]float32
+	for i := 0; i < 2; i++ {
+		cell[i] = int(math.Floor(float64((o[i] + d[i]*tmin) / tm.TileSize)))
+		if cell[i] < 0 {
+			cell[i] = 0
+		} else if cell[i] >= dims[i] {
+			cell[i] = dims[i] - 1
+		}
+
+		switch {
+		case d[i] > 0:
+			step[i] = 1
+			tNext[i] = (float32(cell[i]+1)*tm.TileSize - o[i]) / d[i]
+			tDelta[i] = tm.TileSize / d[i]
+		case d[i] < 0:
+			step[i] = -1
+			tNext[i] = (float32(cell[i])*tm.TileSize - o[i]) / d[i]
+			tDelta[i] = -tm.TileSize / d[i]
+		default:
+			tNext[i] = float32(math.Inf(1))
+			tDelta[i] = float32(math.Inf(1))
+		}
+	}
+
+	t := tmin
+	axis := entryAxis
+	for tm.InBounds(cell[0], cell[1]) {
+		kind := tm.Get(cell[0], cell[1])
+		solid := kind == TileSolid
+		if kind == TileOneWay {
+			solid = axis == 1 && step[1] < 0
+		}
+		if solid {
+			hit := TileHit{Distance: t, Tile: cell, UserData: tm.UserData, Material: tm.Material}
+			if axis < 0 {
+				// the ray started inside this tile
+				hit.Normal = d.Mul(-1.0)
+			} else {
+				hit.Normal[axis] = float32(-step[axis])
+			}
+			return Intersect, hit
+		}
+
+		// step into the next tile the ray crosses
+		axis = 0
+		if tNext[1] < tNext[0] {
+			axis = 1
+		}
+		t = tNext[axis]
+		cell[axis] += step[axis]
+		tNext[axis] += tDelta[axis]
+		if t > tmax || !ray.inRange(t) {
+			break
+		}
+	}
+
+	return NoIntersect, TileHit{}
+}
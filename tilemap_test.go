@@ -0,0 +1,163 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestTileLevel makes a 10x6 map of unit tiles with a solid floor on
+// row 0, a solid wall in column 8 and a one-way platform from (2, 3) to (4, 3).
+func newTestTileLevel() *TileMap {
+	tm := NewTileMap(10, 6, 1.0)
+	for x := 0; x < 10; x++ {
+		tm.Set(x, 0, TileSolid)
+	}
+	for y := 0; y < 6; y++ {
+		tm.Set(8, y, TileSolid)
+	}
+	for x := 2; x <= 4; x++ {
+		tm.Set(x, 3, TileOneWay)
+	}
+	return tm
+}
+
+// newTestPlayerSquare makes a 0.5 unit square with its lower-left corner at x, y.
+func newTestPlayerSquare(x, y float32) *AABSquare {
+	b := NewAABSquare()
+	b.Max = mgl.Vec2{0.5, 0.5}
+	b.Offset = mgl.Vec2{x, y}
+	return b
+}
+
+func TestTileMapCollisionVsAABSquare(t *testing.T) {
+	tm := newTestTileLevel()
+
+	if tm.CollideVsAABSquare(newTestPlayerSquare(1.0, 0.8)) != Intersect {
+		t.Error("TileMap.CollideVsAABSquare() indicated a square in the floor didn't collide.")
+	}
+	if tm.CollideVsAABSquare(newTestPlayerSquare(1.0, 1.0)) != NoIntersect {
+		t.Error("TileMap.CollideVsAABSquare() indicated a square resting on the floor collided.")
+	}
+	if tm.CollideVsAABSquare(newTestPlayerSquare(2.5, 2.8)) != NoIntersect {
+		t.Error("TileMap.CollideVsAABSquare() indicated a square inside a one-way tile collided.")
+	}
+	// squares that start embedded get pushed back out
+	tests := []struct {
+		name     string
+		square   *AABSquare
+		expected mgl.Vec2
+	}{
+		{"floor", newTestPlayerSquare(1.0, 0.8), mgl.Vec2{0, 0.2}},
+		{"floor seam", newTestPlayerSquare(1.9, 0.9), mgl.Vec2{0, 0.1}},
+		{"wall", newTestPlayerSquare(7.8, 2.0), mgl.Vec2{-0.3, 0}},
+		{"corner", newTestPlayerSquare(7.8, 0.9), mgl.Vec2{-0.3, 0.1}},
+		{"clear", newTestPlayerSquare(1.0, 1.0), mgl.Vec2{}},
+	}
+	for _, test := range tests {
+		result, mtv := tm.CollideVsAABSquareWithMTV(test.square)
+		if (result == Intersect) != (test.expected != mgl.Vec2{}) || mtv.Sub(test.expected).Len() > 1e-4 {
+			t.Errorf("%s: TileMap.CollideVsAABSquareWithMTV() returned %d %v instead of %v", test.name, result, mtv, test.expected)
+		}
+		test.square.Offset = test.square.Offset.Add(mtv)
+		if tm.CollideVsAABSquare(test.square) != NoIntersect {
+			t.Errorf("%s: the square still collided after being pushed out by %v", test.name, mtv)
+		}
+	}
+
+	if x, y, ok := tm.TileAt(mgl.Vec2{8.5, 2.5}); !ok || x != 8 || y != 2 || tm.Get(x, y) != TileSolid {
+		t.Errorf("TileMap.TileAt() returned the wrong tile: %d %d %v", x, y, ok)
+	}
+}
+
+func TestTileMapMoveAABSquare(t *testing.T) {
+	tm := newTestTileLevel()
+
+	// falling onto the floor while running towards the wall
+	move := tm.MoveAABSquare(newTestPlayerSquare(6.0, 1.5), mgl.Vec2{3.0, -2.0})
+	if !move.HitX || !move.HitY {
+		t.Errorf("TileMap.MoveAABSquare() didn't hit both the wall and floor: %v", move)
+	}
+	if !move.Delta.ApproxEqual(mgl.Vec2{1.5, -0.5}) {
+		t.Errorf("TileMap.MoveAABSquare() returned the wrong movement: %v", move.Delta)
+	}
+	if !move.Correction.ApproxEqual(mgl.Vec2{-1.5, 1.5}) {
+		t.Errorf("TileMap.MoveAABSquare() returned the wrong correction: %v", move.Correction)
+	}
+
+	// running along the floor doesn't catch on it
+	move = tm.MoveAABSquare(newTestPlayerSquare(1.0, 1.0), mgl.Vec2{2.0, 0.0})
+	if move.HitX || move.HitY || !move.Delta.ApproxEqual(mgl.Vec2{2.0, 0.0}) {
+		t.Errorf("TileMap.MoveAABSquare() stopped a square running along the floor: %v", move)
+	}
+
+	// jumping up through a one-way platform
+	move = tm.MoveAABSquare(newTestPlayerSquare(3.0, 2.0), mgl.Vec2{0.0, 2.0})
+	if move.HitY || !move.Delta.ApproxEqual(mgl.Vec2{0.0, 2.0}) {
+		t.Errorf("TileMap.MoveAABSquare() stopped a square jumping up through a one-way tile: %v", move)
+	}
+
+	// landing on top of a one-way platform
+	move = tm.MoveAABSquare(newTestPlayerSquare(3.0, 4.5), mgl.Vec2{0.0, -1.0})
+	if !move.HitY || !move.Delta.ApproxEqual(mgl.Vec2{0.0, -0.5}) {
+		t.Errorf("TileMap.MoveAABSquare() didn't land a square on a one-way tile: %v", move)
+	}
+
+	// walking sideways through a one-way platform
+	move = tm.MoveAABSquare(newTestPlayerSquare(1.0, 3.2), mgl.Vec2{2.0, 0.0})
+	if move.HitX {
+		t.Errorf("TileMap.MoveAABSquare() stopped a square walking through a one-way tile: %v", move)
+	}
+
+	// moving away from a wall the square is touching
+	move = tm.MoveAABSquare(newTestPlayerSquare(7.5, 1.0), mgl.Vec2{-1.0, 0.0})
+	if move.HitX || !move.Delta.ApproxEqual(mgl.Vec2{-1.0, 0.0}) {
+		t.Errorf("TileMap.MoveAABSquare() stopped a square moving away from a wall: %v", move)
+	}
+}
+
+func TestTileMapCollisionVsRay(t *testing.T) {
+	tm := newTestTileLevel()
+	tm.SetOffset2f(-5.0, 0.0)
+	tm.UserData = "level"
+	tm.Material.SurfaceType = 3
+
+	// along the ground into the wall
+	ray, _ := NewCollisionRay2D(mgl.Vec2{-10.0, 1.5}, mgl.Vec2{1, 0})
+	result, hit := tm.CollideVsRayDetailed(ray)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 13.0, 1e-4) {
+		t.Errorf("TileMap.CollideVsRayDetailed() didn't hit the wall correctly: %d %f", result, hit.Distance)
+	}
+	if hit.Tile != [2]int{8, 1} || hit.Normal != (mgl.Vec2{-1, 0}) {
+		t.Errorf("TileMap.CollideVsRayDetailed() hit tile %v with normal %v", hit.Tile, hit.Normal)
+	}
+	if hit.UserData != "level" || hit.Material.SurfaceType != 3 {
+		t.Errorf("TileMap.CollideVsRayDetailed() returned the wrong user data or material: %v %v", hit.UserData, hit.Material)
+	}
+
+	// down onto a one-way platform
+	ray, _ = NewCollisionRay2D(mgl.Vec2{-2.5, 5.5}, mgl.Vec2{0, -1})
+	result, hit = tm.CollideVsRayDetailed(ray)
+	if result != Intersect || hit.Tile != [2]int{2, 3} || hit.Normal != (mgl.Vec2{0, 1}) {
+		t.Errorf("TileMap.CollideVsRayDetailed() didn't hit the top of a one-way tile: %d %v %v", result, hit.Tile, hit.Normal)
+	}
+
+	// up through a one-way platform to the top of the map
+	ray, _ = NewCollisionRay2D(mgl.Vec2{-2.5, 1.5}, mgl.Vec2{0, 1})
+	if result, _ := tm.CollideVsRay(ray); result != NoIntersect {
+		t.Error("TileMap.CollideVsRay() hit a one-way tile from below.")
+	}
+
+	// a segment that falls short of the wall
+	ray.SetSegment(mgl.Vec2{-10.0, 1.5}, mgl.Vec2{2.0, 1.5})
+	if result, _ := tm.CollideVsRay(ray); result != NoIntersect {
+		t.Error("TileMap.CollideVsRay() hit beyond the ray's MaxDistance.")
+	}
+
+	if _, err := NewCollisionRay2D(mgl.Vec2{}, mgl.Vec2{}); err == nil {
+		t.Error("NewCollisionRay2D() didn't return an error for a zero direction.")
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// Triangle is a single triangle defined by three vertices in local space.
+// The front face is the one where V0, V1, V2 wind counter-clockwise.
+type Triangle struct {
+	// V0 is the first vertex of the triangle.
+	V0 mgl.Vec3
+
+	// V1 is the second vertex of the triangle.
+	V1 mgl.Vec3
+
+	// V2 is the third vertex of the triangle.
+	V2 mgl.Vec3
+
+	// Offset is the world-space location of the that can be considered an offset to the vertices
+	Offset mgl.Vec3
+
+	// CullBackface makes ray casts ignore hits on the back face of the triangle.
+	CullBackface bool
+
+	// Tags provides a way to label a triangle in a custom application
+	// (e.g. labelling a collision as "wall" or "floor").
+	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// triangle is hit, such as the model it was built from.
+	UserData interface{}
+
+	// Material describes the surface of the triangle.
+	Material Material
+}
+
+// TriangleHit describes where a ray hit a triangle.
+type TriangleHit struct {
+	// Distance is the parametric distance along the ray to the hit point.
+	Distance float32
+
+	// U and V are the barycentric coordinates of the hit point such that
+	// the point equals V0*(1-U-V) + V1*U + V2*V.
+	U, V float32
+
+	// Index is the index of the triangle that was hit in a Mesh; it is
+	// always zero for a single Triangle.
+	Index int
+}
+
+// NewTriangle creates a new Triangle object from three vertices.
+func NewTriangle(v0, v1, v2 mgl.Vec3) *Triangle {
+	tri := new(Triangle)
+	tri.V0 = v0
+	tri.V1 = v1
+	tri.V2 = v2
+	return tri
+}
+
+// SetOffset changes the offset of the collision object.
+func (tri *Triangle) SetOffset(offset *mgl.Vec3) {
+	tri.Offset = *offset
+}
+
+// SetOffset3f changes the offset of the collision object.
+func (tri *Triangle) SetOffset3f(x, y, z float32) {
+	tri.Offset[0] = x
+	tri.Offset[1] = y
+	tri.Offset[2] = z
+}
+
+// Validate checks the Triangle for non-finite values and a triangle
+// that has no area.
+func (tri *Triangle) Validate() error {
+	if err := validateFinite("Triangle", "V0", tri.V0[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("Triangle", "V1", tri.V1[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("Triangle", "V2", tri.V2[:]); err != nil {
+		return err
+	}
+	if err := validateFinite("Triangle", "Offset", tri.Offset[:]); err != nil {
+		return err
+	}
+	normal := tri.V1.Sub(tri.V0).Cross(tri.V2.Sub(tri.V0))
+	if normal.Dot(normal) == 0 {
+		return &ShapeError{Shape: "Triangle", Field: "V0", Err: ErrDegenerateTriangle}
+	}
+	return nil
+}
+
+// vertices returns the world-space vertices of the triangle.
+func (tri *Triangle) vertices() (mgl.Vec3, mgl.Vec3, mgl.Vec3) {
+	return tri.V0.Add(tri.Offset), tri.V1.Add(tri.Offset), tri.V2.Add(tri.Offset)
+}
+
+// CollideVsRay tests to see if a raycast intersects the triangle and returns
+// the distance along the ray to the hit.
+func (tri *Triangle) CollideVsRay(ray *CollisionRay) (int, float32) {
+	result, hit := tri.CollideVsRayDetailed(ray)
+	return result, hit.Distance
+}
+
+// CollideVsRayDetailed tests to see if a raycast intersects the triangle and
+// returns the distance and the barycentric coordinates of the hit.
+func (tri *Triangle) CollideVsRayDetailed(ray *CollisionRay) (int, TriangleHit) {
+	var hit TriangleHit
+	v0, v1, v2 := tri.vertices()
+	result, dist, u, v := RayVsTriangle(ray, v0, v1, v2, tri.CullBackface)
+	if result == Intersect {
+		hit.Distance = dist
+		hit.U = u
+		hit.V = v
+	}
+	return result, hit
+}
+
+// CollideVsSphere tests a collision between a triangle and a sphere.
+func (tri *Triangle) CollideVsSphere(s *Sphere) int {
+	v0, v1, v2 := tri.vertices()
+	if sphereVsTriangle(s.Center.Add(s.Offset), s.Radius, v0, v1, v2) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsAABBox tests a collision between a triangle and an AABBox.
+func (tri *Triangle) CollideVsAABBox(b *AABBox) int {
+	v0, v1, v2 := tri.vertices()
+	if aabbVsTriangle(b.Min.Add(b.Offset), b.Max.Add(b.Offset), v0, v1, v2) {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsPlane tests a collision between a triangle and a plane. Like the
+// other shapes, the triangle only fails to intersect if it lies completely
+// behind the plane.
+func (tri *Triangle) CollideVsPlane(p *Plane) int {
+	v0, v1, v2 := tri.vertices()
+	if p.Distance(v0) < 0 && p.Distance(v1) < 0 && p.Distance(v2) < 0 {
+		return NoIntersect
+	}
+	return Intersect
+}
+
+// RayVsTriangle performs a Möller–Trumbore ray vs triangle intersection test
+// against the world-space vertices v0, v1, v2. If cullBackface is true, hits
+// on the back face (clockwise winding as seen from the ray) are ignored.
+// On a hit it returns the distance along the ray and the barycentric
+// coordinates u and v of the hit point.
+func RayVsTriangle(ray *CollisionRay, v0, v1, v2 mgl.Vec3, cullBackface bool) (int, float32, float32, float32) {
+	edge1 := v1.Sub(v0)
+	edge2 := v2.Sub(v0)
+
+	pvec := ray.direction.Cross(edge2)
+	det := edge1.Dot(pvec)
+
+	if cullBackface {
+		if det < rayEpsilon {
+			return NoIntersect, 0.0, 0.0, 0.0
+		}
+	} else if det > -rayEpsilon && det < rayEpsilon {
+		return NoIntersect, 0.0, 0.0, 0.0
+	}
+	invDet := 1.0 / det
+
+	tvec := ray.Origin.Sub(v0)
+	u := tvec.Dot(pvec) * invDet
+	if u < 0.0 || u > 1.0 {
+		return NoIntersect, 0.0, 0.0, 0.0
+	}
+
+	qvec := tvec.Cross(edge1)
+	v := ray.direction.Dot(qvec) * invDet
+	if v < 0.0 || u+v > 1.0 {
+		return NoIntersect, 0.0, 0.0, 0.0
+	}
+
+	t := edge2.Dot(qvec) * invDet
+	if t < 0.0 || !ray.inRange(t) {
+		return NoIntersect, 0.0, 0.0, 0.0
+	}
+
+	return Intersect, t, u, v
+}
+
+// closestPointOnTriangle returns the point on the triangle a, b, c that is
+// closest to p.
+func closestPointOnTriangle(p, a, b, c mgl.Vec3) mgl.Vec3 {
+	u, v, w := closestTriangleWeights(p, a, b, c)
+	return a.Mul(u).Add(b.Mul(v)).Add(c.Mul(w))
+}
+
+// closestTriangleWeights returns the barycentric weights of the point on the
+// triangle a, b, c that is closest to p. Implementation based on Ericson's
+// Real-Time Collision Detection.
+func closestTriangleWeights(p, a, b, c mgl.Vec3) (float32, float32, float32) {
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+
+	// vertex region outside a
+	ap := p.Sub(a)
+	d1 := ab.Dot(ap)
+	d2 := ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return 1, 0, 0
+	}
+
+	// vertex region outside b
+	bp := p.Sub(b)
+	d3 := ab.Dot(bp)
+	d4 := ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return 0, 1, 0
+	}
+
+	// edge region of ab
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		return 1 - v, v, 0
+	}
+
+	// vertex region outside c
+	cp := p.Sub(c)
+	d5 := ab.Dot(cp)
+	d6 := ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return 0, 0, 1
+	}
+
+	// edge region of ac
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		return 1 - w, 0, w
+	}
+
+	// edge region of bc
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return 0, 1 - w, w
+	}
+
+	// inside the face region
+	denom := 1.0 / (va + vb + vc)
+	v := vb * denom
+	w := vc * denom
+	return 1 - v - w, v, w
+}
+
+// sphereVsTriangle tests a world-space sphere against a world-space triangle.
+func sphereVsTriangle(center mgl.Vec3, radius float32, a, b, c mgl.Vec3) bool {
+	closest := closestPointOnTriangle(center, a, b, c)
+	delta := closest.Sub(center)
+	return delta.Dot(delta) <= radius*radius
+}
+
+// aabbVsTriangle tests a world-space box against a world-space triangle
+// using the separating axis test from Akenine-Möller's
+// "Fast 3D Triangle-Box Overlap Testing".
+func aabbVsTriangle(min, max mgl.Vec3, a, b, c mgl.Vec3) bool {
+	center := min.Add(max).Mul(0.5)
+	half := max.Sub(min).Mul(0.5)
+
+	// move everything so that the box is centered on the origin
+	v0 := a.Sub(center)
+	v1 := b.Sub(center)
+	v2 := c.Sub(center)
+
+	// test the box face normals which amounts to comparing the
+	// triangle's bounds to the box
+	for i := 0; i < 3; i++ {
+		triMin := min32(min32(v0[i], v1[i]), v2[i])
+		triMax := max32(max32(v0[i], v1[i]), v2[i])
+		if triMin > half[i] || triMax < -half[i] {
+			return false
+		}
+	}
+
+	// test the nine axes made from the cross products of the box axes
+	// and the triangle edges
+	edges := [3]mgl.Vec3{v1.Sub(v0), v2.Sub(v1), v0.Sub(v2)}
+	boxAxes := [3]mgl.Vec3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	for _, e := range edges {
+		for _, u := range boxAxes {
+			axis := u.Cross(e)
+			p0 := v0.Dot(axis)
+			p1 := v1.Dot(axis)
+			p2 := v2.Dot(axis)
+			r := half[0]*fabs32(axis[0]) + half[1]*fabs32(axis[1]) + half[2]*fabs32(axis[2])
+			if min32(min32(p0, p1), p2) > r || max32(max32(p0, p1), p2) < -r {
+				return false
+			}
+		}
+	}
+
+	// finally test the plane of the triangle against the box
+	normal := edges[0].Cross(edges[1])
+	d := normal.Dot(v0)
+	r := half[0]*fabs32(normal[0]) + half[1]*fabs32(normal[1]) + half[2]*fabs32(normal[2])
+	return fabs32(d) <= r
+}
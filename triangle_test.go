@@ -0,0 +1,139 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestTriangleCollisionVsRay(t *testing.T) {
+	// triangle in the z=0 plane facing +z
+	tri := NewTriangle(mgl.Vec3{0, 0, 0}, mgl.Vec3{2, 0, 0}, mgl.Vec3{0, 2, 0})
+
+	var r1 CollisionRay
+	r1.Origin = mgl.Vec3{0.5, 0.5, 5.0}
+	r1.SetDirection(mgl.Vec3{0.0, 0.0, -1.0})
+
+	intersect, hit := tri.CollideVsRayDetailed(&r1)
+	if intersect != Intersect {
+		t.Fatal("Triangle.CollideVsRay() indicated a ray didn't intersect that should have.")
+	}
+	if !mgl.FloatEqual(hit.Distance, 5.0) {
+		t.Errorf("Triangle.CollideVsRay() returned the wrong distance: %f", hit.Distance)
+	}
+	if !mgl.FloatEqual(hit.U, 0.25) || !mgl.FloatEqual(hit.V, 0.25) {
+		t.Errorf("Triangle.CollideVsRay() returned the wrong barycentrics: %f, %f", hit.U, hit.V)
+	}
+
+	// a segment that stops short of the triangle
+	r1.SetSegment(mgl.Vec3{0.5, 0.5, 5.0}, mgl.Vec3{0.5, 0.5, 1.0})
+	intersect, _ = tri.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Triangle.CollideVsRay() indicated a segment intersected that shouldn't have.")
+	}
+	r1.MaxDistance = 0.0
+
+	// outside of the edges
+	r1.Origin = mgl.Vec3{1.5, 1.5, 5.0}
+	intersect, _ = tri.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Triangle.CollideVsRay() indicated a ray intersected that shouldn't have.")
+	}
+
+	// pointing away
+	r1.Origin = mgl.Vec3{0.5, 0.5, 5.0}
+	r1.SetDirection(mgl.Vec3{0.0, 0.0, 1.0})
+	intersect, _ = tri.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Triangle.CollideVsRay() indicated a ray pointed away intersected.")
+	}
+
+	// hit the back face
+	r1.Origin = mgl.Vec3{0.5, 0.5, -5.0}
+	intersect, _ = tri.CollideVsRay(&r1)
+	if intersect != Intersect {
+		t.Error("Triangle.CollideVsRay() indicated a ray didn't intersect the back face.")
+	}
+	tri.CullBackface = true
+	intersect, _ = tri.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Triangle.CollideVsRay() indicated a ray intersected a culled back face.")
+	}
+
+	// offset the triangle
+	tri.CullBackface = false
+	tri.SetOffset3f(10, 0, 0)
+	intersect, _ = tri.CollideVsRay(&r1)
+	if intersect != NoIntersect {
+		t.Error("Triangle.CollideVsRay() indicated a ray intersected an offset triangle.")
+	}
+	r1.Origin = mgl.Vec3{10.5, 0.5, -5.0}
+	intersect, _ = tri.CollideVsRay(&r1)
+	if intersect != Intersect {
+		t.Error("Triangle.CollideVsRay() indicated a ray didn't intersect an offset triangle.")
+	}
+}
+
+func TestTriangleCollisionVsSphere(t *testing.T) {
+	tri := NewTriangle(mgl.Vec3{0, 0, 0}, mgl.Vec3{2, 0, 0}, mgl.Vec3{0, 2, 0})
+
+	sphere := Sphere{Center: mgl.Vec3{0.5, 0.5, 0.9}, Radius: 1.0}
+	if tri.CollideVsSphere(&sphere) != Intersect {
+		t.Error("Triangle.CollideVsSphere() indicated a sphere didn't collide that should have.")
+	}
+
+	sphere = Sphere{Center: mgl.Vec3{0.5, 0.5, 1.1}, Radius: 1.0}
+	if tri.CollideVsSphere(&sphere) != NoIntersect {
+		t.Error("Triangle.CollideVsSphere() indicated a sphere collided that should not have.")
+	}
+
+	// near the hypotenuse but off of the face
+	sphere = Sphere{Center: mgl.Vec3{2, 2, 0}, Radius: 1.0}
+	if tri.CollideVsSphere(&sphere) != NoIntersect {
+		t.Error("Triangle.CollideVsSphere() indicated a sphere collided that should not have.")
+	}
+	sphere = Sphere{Center: mgl.Vec3{1.5, 1.5, 0}, Radius: 1.0}
+	if tri.CollideVsSphere(&sphere) != Intersect {
+		t.Error("Triangle.CollideVsSphere() indicated a sphere didn't collide that should have.")
+	}
+}
+
+func TestTriangleCollisionVsAABBox(t *testing.T) {
+	tri := NewTriangle(mgl.Vec3{0, 0, 0}, mgl.Vec3{2, 0, 0}, mgl.Vec3{0, 2, 0})
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{-0.5, -0.5, -0.5}
+	b1.Max = mgl.Vec3{0.5, 0.5, 0.5}
+	if tri.CollideVsAABBox(&b1) != Intersect {
+		t.Error("Triangle.CollideVsAABBox() indicated a box didn't collide that should have.")
+	}
+
+	// inside the triangle's bounds, but past the hypotenuse
+	b1.Offset = mgl.Vec3{1.8, 1.8, 0}
+	if tri.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("Triangle.CollideVsAABBox() indicated a box collided that should not have.")
+	}
+
+	b1.Offset = mgl.Vec3{0.5, 0.5, 2.0}
+	if tri.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("Triangle.CollideVsAABBox() indicated a box collided that should not have.")
+	}
+}
+
+func TestTriangleCollisionVsPlane(t *testing.T) {
+	tri := NewTriangle(mgl.Vec3{0, 0, 0}, mgl.Vec3{2, 0, 0}, mgl.Vec3{0, 2, 0})
+
+	planeNormal := mgl.Vec3{1.0, 0.0, 0.0}
+	p := NewPlaneFromNormalAndPoint(planeNormal, mgl.Vec3{1, 0, 0})
+	if tri.CollideVsPlane(p) != Intersect {
+		t.Error("Triangle.CollideVsPlane() indicated a triangle didn't intersect that should have.")
+	}
+
+	p = NewPlaneFromNormalAndPoint(planeNormal, mgl.Vec3{3, 0, 0})
+	if tri.CollideVsPlane(p) != NoIntersect {
+		t.Error("Triangle.CollideVsPlane() indicated a triangle intersected that should not have.")
+	}
+}
@@ -0,0 +1,398 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// VoxelGrid is a collision shape made from a dense grid of equally sized
+// cube voxels that are each either solid or empty. The occupancy is stored
+// one bit per voxel so that large block worlds can be tested without
+// turning every voxel into an AABBox.
+type VoxelGrid struct {
+	// Width is the number of voxels along the X axis.
+	Width int
+
+	// Height is the number of voxels along the Y axis.
+	Height int
+
+	// Depth is the number of voxels along the Z axis.
+	Depth int
+
+	// CellSize is the length of the sides of each voxel.
+	CellSize float32
+
+	// Offset is the world-space location of the minimum corner of the grid
+	Offset mgl.Vec3
+
+	// Tags provides a way to label a voxel grid in a custom application
+	// (e.g. labelling a collision as "chunk" or "terrain").
+	Tags []string
+
+	// TagSet holds the same kind of labels as Tags interned by a TagRegistry
+	// so that they can be tested quickly.
+	TagSet TagSet
+
+	// UserData holds whatever the application wants to get back when the
+	// voxel grid is hit, such as the world chunk it was built from.
+	UserData interface{}
+
+	// Material describes the surface of the voxel grid.
+	Material Material
+
+	// bits holds one occupancy bit per voxel in X, then Y, then Z order.
+	bits []uint64
+}
+
+// VoxelHit describes where a ray cast hit a VoxelGrid.
+type VoxelHit struct {
+	// Distance is the distance along the ray to where it enters the voxel.
+	Distance float32
+
+	// Voxel is the X, Y and Z coordinate of the voxel that was hit.
+	Voxel [3]int
+
+	// Normal is the normal of the voxel face the ray entered through. Rays
+	// that start inside a solid voxel get a normal facing back along the ray.
+	Normal mgl.Vec3
+
+	// UserData is the UserData of the voxel grid.
+	UserData interface{}
+
+	// Material is the Material of the voxel grid.
+	Material Material
+}
+
+// NewVoxelGrid creates a new, empty VoxelGrid object with the given number
+// of voxels on each axis.
+func NewVoxelGrid(width, height, depth int, cellSize float32) *VoxelGrid {
+	vg := new(VoxelGrid)
+	vg.Width = width
+	vg.Height = height
+	vg.Depth = depth
+	vg.CellSize = cellSize
+	if width > 0 && height > 0 && depth > 0 {
+		vg.bits = make([]uint64, (width*height*depth+63)/64)
+	}
+	return vg
+}
+
+// SetOffset changes the offset of the collision object.
+func (vg *VoxelGrid) SetOffset(offset *mgl.Vec3) {
+	vg.Offset = *offset
+}
+
+// SetOffset3f changes the offset of the collision object.
+func (vg *VoxelGrid) SetOffset3f(x, y, z float32) {
+	vg.Offset[0] = x
+	vg.Offset[1] = y
+	vg.Offset[2] = z
+}
+
+// Validate checks the VoxelGrid for non-finite values, a grid without any
+// voxels or storage that doesn't match the grid and a cell size that
+// isn't positive.
+func (vg *VoxelGrid) Validate() error {
+	if vg.Width <= 0 || vg.Height <= 0 || vg.Depth <= 0 || len(vg.bits)*64 < vg.Width*vg.Height*vg.Depth {
+		return &ShapeError{Shape: "VoxelGrid", Field: "Width", Err: ErrInvalidDimensions}
+	}
+	if err := validateFinite("VoxelGrid", "Offset", vg.Offset[:]); err != nil {
+		return err
+	}
+	if !isFinite32(vg.CellSize) {
+		return &ShapeError{Shape: "VoxelGrid", Field: "CellSize", Err: ErrNonFinite}
+	}
+	if vg.CellSize <= 0 {
+		return &ShapeError{Shape: "VoxelGrid", Field: "CellSize", Err: ErrNegativeSize}
+	}
+	return nil
+}
+
+// InBounds returns true if the voxel coordinate is inside the grid.
+func (vg *VoxelGrid) InBounds(x, y, z int) bool {
+	return x >= 0 && y >= 0 && z >= 0 && x < vg.Width && y < vg.Height && z < vg.Depth
+}
+
+// index returns the bit index of the voxel coordinate.
+func (vg *VoxelGrid) index(x, y, z int) int {
+	return (z*vg.Height+y)*vg.Width + x
+}
+
+// Set marks the voxel at the coordinate as solid or empty. Coordinates
+// outside of the grid are ignored. The storage for the voxels is grown to
+// fit the grid if it was built without NewVoxelGrid or has been resized.
+func (vg *VoxelGrid) Set(x, y, z int, solid bool) {
+	if !vg.InBounds(x, y, z) {
+		return
+	}
+	i := vg.index(x, y, z)
+	if i/64 >= len(vg.bits) {
+		if !solid {
+			return
+		}
+		bits := make([]uint64, (vg.Width*vg.Height*vg.Depth+63)/64)
+		copy(bits, vg.bits)
+		vg.bits = bits
+	}
+	if solid {
+		vg.bits[i/64] |= 1 << uint(i%64)
+	} else {
+		vg.bits[i/64] &^= 1 << uint(i%64)
+	}
+}
+
+// IsSolid returns true if the voxel at the coordinate is solid. Coordinates
+// outside of the grid, or past the storage of a grid that was never Set,
+// are always empty.
+func (vg *VoxelGrid) IsSolid(x, y, z int) bool {
+	if !vg.InBounds(x, y, z) {
+		return false
+	}
+	i := vg.index(x, y, z)
+	if i/64 >= len(vg.bits) {
+		return false
+	}
+	return vg.bits[i/64]&(1<<uint(i%64)) != 0
+}
+
+// VoxelBounds returns the world-space minimum and maximum corners of the
+// voxel at the coordinate.
+func (vg *VoxelGrid) VoxelBounds(x, y, z int) (mgl.Vec3, mgl.Vec3) {
+	min := mgl.Vec3{float32(x), float32(y), float32(z)}.Mul(vg.CellSize).Add(vg.Offset)
+	return min, min.Add(mgl.Vec3{vg.CellSize, vg.CellSize, vg.CellSize})
+}
+
+// dims returns the number of voxels on each axis.
+func (vg *VoxelGrid) dims() [3]int {
+	return [3]int{vg.Width, vg.Height, vg.Depth}
+}
+
+// voxelRange returns the range of voxel coordinates, inclusive, covered by
+// the world-space bounds. The last return value is false if the bounds
+// don't overlap the grid.
+func (vg *VoxelGrid) voxelRange(min, max mgl.Vec3) ([3]int, [3]int, bool) {
+	var lo, hi [3]int
+	if vg.CellSize <= 0 {
+		return lo, hi, false
+	}
+	dims := vg.dims()
+	for i := 0; i < 3; i++ {
+		fmin := (min[i] - vg.Offset[i]) / vg.CellSize
+		fmax := (max[i] - vg.Offset[i]) / vg.CellSize
+		if fmax < 0 || fmin > float32(dims[i]) || dims[i] <= 0 {
+			return lo, hi, false
+		}
+		lo[i] = int(math.Floor(float64(max32(fmin, 0))))
+		hi[i] = int(math.Floor(float64(fmax)))
+		if lo[i] >= dims[i] {
+			lo[i] = dims[i] - 1
+		}
+		if hi[i] >= dims[i] {
+			hi[i] = dims[i] - 1
+		}
+	}
+	return lo, hi, true
+}
+
+// OverlapAABBox appends the coordinates of every solid voxel touching the
+// box to touched and returns the result.
+func (vg *VoxelGrid) OverlapAABBox(b *AABBox, touched [][3]int) [][3]int {
+	lo, hi, ok := vg.voxelRange(b.Min.Add(b.Offset), b.Max.Add(b.Offset))
+	if !ok {
+		return touched
+	}
+	for z := lo[2]; z <= hi[2]; z++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for x := lo[0]; x <= hi[0]; x++ {
+				if vg.IsSolid(x, y, z) {
+					touched = append(touched, [3]int{x, y, z})
+				}
+			}
+		}
+	}
+	return touched
+}
+
+// OverlapSphere appends the coordinates of every solid voxel touching the
+// sphere to touched and returns the result.
+func (vg *VoxelGrid) OverlapSphere(s *Sphere, touched [][3]int) [][3]int {
+	center := s.Center.Add(s.Offset)
+	r := mgl.Vec3{s.Radius, s.Radius, s.Radius}
+	lo, hi, ok := vg.voxelRange(center.Sub(r), center.Add(r))
+	if !ok {
+		return touched
+	}
+	rsq := s.Radius * s.Radius
+	for z := lo[2]; z <= hi[2]; z++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for x := lo[0]; x <= hi[0]; x++ {
+				if !vg.IsSolid(x, y, z) {
+					continue
+				}
+
+				// find the distance from the closest point on the voxel
+				min, max := vg.VoxelBounds(x, y, z)
+				var distSq float32
+				for i := 0; i < 3; i++ {
+					d := center[i] - min32(max32(center[i], min[i]), max[i])
+					distSq += d * d
+				}
+				if distSq <= rsq {
+					touched = append(touched, [3]int{x, y, z})
+				}
+			}
+		}
+	}
+	return touched
+}
+
+// CollideVsAABBox tests a collision between a voxel grid and an AABBox.
+func (vg *VoxelGrid) CollideVsAABBox(b *AABBox) int {
+	var buffer [1][3]int
+	if len(vg.OverlapAABBox(b, buffer[:0])) > 0 {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsSphere tests a collision between a voxel grid and a sphere.
+func (vg *VoxelGrid) CollideVsSphere(s *Sphere) int {
+	var buffer [1][3]int
+	if len(vg.OverlapSphere(s, buffer[:0])) > 0 {
+		return Intersect
+	}
+	return NoIntersect
+}
+
+// CollideVsPlane tests a collision between a voxel grid and a plane. The
+// grid only fails to intersect if all of its solid voxels lie completely
+// behind the plane.
+func (vg *VoxelGrid) CollideVsPlane(p *Plane) int {
+	half := vg.CellSize * 0.5
+	extent := half * (fabs32(p.Normal[0]) + fabs32(p.Normal[1]) + fabs32(p.Normal[2]))
+	for z := 0; z < vg.Depth; z++ {
+		for y := 0; y < vg.Height; y++ {
+			for x := 0; x < vg.Width; x++ {
+				if !vg.IsSolid(x, y, z) {
+					continue
+				}
+				min, _ := vg.VoxelBounds(x, y, z)
+				center := min.Add(mgl.Vec3{half, half, half})
+				if p.Distance(center)+extent >= 0 {
+					return Intersect
+				}
+			}
+		}
+	}
+	return NoIntersect
+}
+
+// CollideVsRay tests to see if a raycast hits a solid voxel and returns the
+// distance to the first one hit.
+func (vg *VoxelGrid) CollideVsRay(ray *CollisionRay) (int, float32) {
+	result, hit := vg.CollideVsRayDetailed(ray)
+	return result, hit.Distance
+}
+
+// CollideVsRayDetailed tests to see if a raycast hits a solid voxel and
+// returns the distance, voxel coordinate and face normal of the first one
+// hit. Voxels are visited in the order the ray passes through them using
+// the "A Fast Voxel Traversal Algorithm for Ray Tracing" method from
+// Amanatides and Woo.
+func (vg *VoxelGrid) CollideVsRayDetailed(ray *CollisionRay) (int, VoxelHit) {
+	if !ray.valid() || vg.CellSize <= 0 || vg.Width <= 0 || vg.Height <= 0 || vg.Depth <= 0 {
+		return NoIntersect, VoxelHit{}
+	}
+
+	// work in the grid's local space
+	o := ray.Origin.Sub(vg.Offset)
+	d := ray.direction
+	dims := vg.dims()
+
+	// clip the ray to the grid's bounds, remembering which face it entered
+	tmin := float32(0.0)
+	tmax := float32(math.Inf(1))
+	entryAxis := -1
+	for i := 0; i < 3; i++ {
+		size := float32(dims[i]) * vg.CellSize
+		if d[i] == 0 {
+			if o[i] < 0 || o[i] > size {
+				return NoIntersect, VoxelHit{}
+			}
+			continue
+		}
+		t1 := (0 - o[i]) / d[i]
+		t2 := (size - o[i]) / d[i]
+		near := min32(t1, t2)
+		if near > tmin {
+			tmin = near
+			entryAxis = i
+		}
+		tmax = min32(tmax, max32(t1, t2))
+	}
+	if tmin > tmax || !ray.inRange(tmin) {
+		return NoIntersect, VoxelHit{}
+	}
+
+	// set up the walk from the voxel where the ray enters the grid
+	var cell, step [3]int
+	var tNext, tDelta [3]float32
+	for i := 0; i < 3; i++ {
+		cell[i] = int(math.Floor(float64((o[i] + d[i]*tmin) / vg.CellSize)))
+		if cell[i] < 0 {
+			cell[i] = 0
+		} else if cell[i] >= dims[i] {
+			cell[i] = dims[i] - 1
+		}
+
+		switch {
+		case d[i] > 0:
+			step[i] = 1
+			tNext[i] = (float32(cell[i]+1)*vg.CellSize - o[i]) / d[i]
+			tDelta[i] = vg.CellSize / d[i]
+		case d[i] < 0:
+			step[i] = -1
+			tNext[i] = (float32(cell[i])*vg.CellSize - o[i]) / d[i]
+			tDelta[i] = -vg.CellSize / d[i]
+		default:
+			tNext[i] = float32(math.Inf(1))
+			tDelta[i] = float32(math.Inf(1))
+		}
+	}
+
+	t := tmin
+	axis := entryAxis
+	for vg.InBounds(cell[0], cell[1], cell[2]) {
+		if vg.IsSolid(cell[0], cell[1], cell[2]) {
+			hit := VoxelHit{Distance: t, Voxel: cell, UserData: vg.UserData, Material: vg.Material}
+			if axis < 0 {
+				// the ray started inside this voxel
+				hit.Normal = d.Mul(-1.0)
+			} else {
+				hit.Normal[axis] = float32(-step[axis])
+			}
+			return Intersect, hit
+		}
+
+		// step into the next voxel the ray crosses
+		axis = 0
+		if tNext[1] < tNext[axis] {
+			axis = 1
+		}
+		if tNext[2] < tNext[axis] {
+			axis = 2
+		}
+		t = tNext[axis]
+		cell[axis] += step[axis]
+		tNext[axis] += tDelta[axis]
+		if t > tmax || !ray.inRange(t) {
+			break
+		}
+	}
+
+	return NoIntersect, VoxelHit{}
+}
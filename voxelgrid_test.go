@@ -0,0 +1,177 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestVoxelFloor makes an 8x8x8 grid of unit voxels with a solid floor
+// along y=0 and a single solid pillar voxel at (4, 1, 4).
+func newTestVoxelFloor() *VoxelGrid {
+	vg := NewVoxelGrid(8, 8, 8, 1.0)
+	for z := 0; z < 8; z++ {
+		for x := 0; x < 8; x++ {
+			vg.Set(x, 0, z, true)
+		}
+	}
+	vg.Set(4, 1, 4, true)
+	return vg
+}
+
+func TestVoxelGridSet(t *testing.T) {
+	vg := NewVoxelGrid(3, 4, 5, 1.0)
+	vg.Set(2, 3, 4, true)
+	if !vg.IsSolid(2, 3, 4) {
+		t.Error("VoxelGrid.IsSolid() returned false for a voxel that was set.")
+	}
+	if vg.IsSolid(1, 3, 4) {
+		t.Error("VoxelGrid.IsSolid() returned true for a voxel that wasn't set.")
+	}
+	vg.Set(2, 3, 4, false)
+	if vg.IsSolid(2, 3, 4) {
+		t.Error("VoxelGrid.IsSolid() returned true for a voxel that was cleared.")
+	}
+
+	// out of bounds voxels are ignored and empty
+	vg.Set(3, 0, 0, true)
+	if vg.IsSolid(3, 0, 0) || vg.IsSolid(0, 1, 0) {
+		t.Error("VoxelGrid.Set() wrapped an out of bounds voxel into the grid.")
+	}
+}
+
+func TestVoxelGridWithoutStorage(t *testing.T) {
+	// a grid built without NewVoxelGrid starts out empty
+	vg := &VoxelGrid{Width: 4, Height: 4, Depth: 4, CellSize: 1.0}
+	if vg.IsSolid(3, 3, 3) {
+		t.Error("VoxelGrid.IsSolid() returned true for a grid without storage.")
+	}
+	ray, _ := NewCollisionRay(mgl.Vec3{0.5, 10.0, 0.5}, mgl.Vec3{0, -1, 0})
+	if result, _ := vg.CollideVsRay(ray); result != NoIntersect {
+		t.Error("VoxelGrid.CollideVsRay() hit a grid without storage.")
+	}
+	vg.Set(0, 0, 0, true)
+	if result, dist := vg.CollideVsRay(ray); result != Intersect || !mgl.FloatEqualThreshold(dist, 9.0, 1e-4) {
+		t.Errorf("VoxelGrid.CollideVsRay() returned %d %f after setting a voxel in a grid without storage", result, dist)
+	}
+
+	// growing the grid after it was made
+	vg.Depth = 8
+	if vg.IsSolid(3, 3, 7) {
+		t.Error("VoxelGrid.IsSolid() returned true past the storage of a grown grid.")
+	}
+	vg.Set(3, 3, 7, true)
+	if !vg.IsSolid(3, 3, 7) || !vg.IsSolid(0, 0, 0) {
+		t.Error("VoxelGrid.Set() didn't grow the storage of a grown grid.")
+	}
+}
+
+func TestVoxelGridCollisionVsRay(t *testing.T) {
+	vg := newTestVoxelFloor()
+	vg.SetOffset3f(-4.0, -1.0, -4.0)
+	vg.UserData = "chunk"
+	vg.Material.SurfaceType = 5
+
+	// straight down onto the floor
+	ray, _ := NewCollisionRay(mgl.Vec3{-1.5, 5.0, -1.5}, mgl.Vec3{0, -1, 0})
+	result, hit := vg.CollideVsRayDetailed(ray)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 5.0, 1e-4) {
+		t.Errorf("VoxelGrid.CollideVsRayDetailed() didn't hit the floor correctly: %d %f", result, hit.Distance)
+	}
+	if hit.Voxel != [3]int{2, 0, 2} || hit.Normal != (mgl.Vec3{0, 1, 0}) {
+		t.Errorf("VoxelGrid.CollideVsRayDetailed() hit voxel %v with normal %v", hit.Voxel, hit.Normal)
+	}
+	if hit.UserData != "chunk" || hit.Material.SurfaceType != 5 {
+		t.Errorf("VoxelGrid.CollideVsRayDetailed() returned the wrong user data or material: %v %v", hit.UserData, hit.Material)
+	}
+
+	// across the grid into the side of the pillar
+	ray, _ = NewCollisionRay(mgl.Vec3{-10.0, 0.5, 0.5}, mgl.Vec3{1, 0, 0})
+	result, hit = vg.CollideVsRayDetailed(ray)
+	if result != Intersect || !mgl.FloatEqualThreshold(hit.Distance, 10.0, 1e-4) {
+		t.Errorf("VoxelGrid.CollideVsRayDetailed() didn't hit the pillar correctly: %d %f", result, hit.Distance)
+	}
+	if hit.Voxel != [3]int{4, 1, 4} || hit.Normal != (mgl.Vec3{-1, 0, 0}) {
+		t.Errorf("VoxelGrid.CollideVsRayDetailed() hit voxel %v with normal %v", hit.Voxel, hit.Normal)
+	}
+
+	// a diagonal ray that lands on the floor
+	ray, _ = NewCollisionRay(mgl.Vec3{-3.5, 3.0, -3.5}, mgl.Vec3{1, -1, 0})
+	result, hit = vg.CollideVsRayDetailed(ray)
+	if result != Intersect || hit.Voxel != [3]int{3, 0, 0} || hit.Normal != (mgl.Vec3{0, 1, 0}) {
+		t.Errorf("VoxelGrid.CollideVsRayDetailed() didn't hit the floor diagonally: %d %v %v", result, hit.Voxel, hit.Normal)
+	}
+
+	// a segment that stops above the floor
+	ray, _ = NewCollisionRay(mgl.Vec3{-1.5, 5.0, -1.5}, mgl.Vec3{0, -1, 0})
+	ray.MaxDistance = 4.0
+	if result, _ := vg.CollideVsRay(ray); result != NoIntersect {
+		t.Error("VoxelGrid.CollideVsRay() hit beyond the ray's MaxDistance.")
+	}
+
+	// above the floor heading up and out of the grid
+	ray, _ = NewCollisionRay(mgl.Vec3{-1.5, 2.0, -1.5}, mgl.Vec3{1, 1, 1})
+	if result, _ := vg.CollideVsRay(ray); result != NoIntersect {
+		t.Error("VoxelGrid.CollideVsRay() hit when the ray was heading away.")
+	}
+
+	// starting inside a solid voxel
+	ray, _ = NewCollisionRay(mgl.Vec3{0.5, -0.5, 0.5}, mgl.Vec3{0, 1, 0})
+	result, hit = vg.CollideVsRayDetailed(ray)
+	if result != Intersect || hit.Distance != 0.0 || hit.Normal != (mgl.Vec3{0, -1, 0}) {
+		t.Errorf("VoxelGrid.CollideVsRayDetailed() didn't hit from inside a voxel: %d %f %v", result, hit.Distance, hit.Normal)
+	}
+}
+
+func TestVoxelGridOverlap(t *testing.T) {
+	vg := newTestVoxelFloor()
+
+	var b1 AABBox
+	b1.Min = mgl.Vec3{3.5, 0.5, 3.5}
+	b1.Max = mgl.Vec3{4.5, 1.5, 4.5}
+	touched := vg.OverlapAABBox(&b1, nil)
+	if len(touched) != 5 {
+		t.Errorf("VoxelGrid.OverlapAABBox() touched %d voxels instead of 5: %v", len(touched), touched)
+	}
+	if vg.CollideVsAABBox(&b1) != Intersect {
+		t.Error("VoxelGrid.CollideVsAABBox() indicated a box didn't collide that should have.")
+	}
+	b1.Offset = mgl.Vec3{-2.0, 1.0, 0.0}
+	if vg.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("VoxelGrid.CollideVsAABBox() indicated a box above the floor collided.")
+	}
+	b1.Offset = mgl.Vec3{-10.0, 0.0, 0.0}
+	if vg.CollideVsAABBox(&b1) != NoIntersect {
+		t.Error("VoxelGrid.CollideVsAABBox() indicated a box beside the grid collided.")
+	}
+
+	// a sphere resting on the floor in the middle of a voxel only touches that voxel
+	sphere := Sphere{Center: mgl.Vec3{1.5, 1.4, 1.5}, Radius: 0.45}
+	touched = vg.OverlapSphere(&sphere, touched[:0])
+	if len(touched) != 1 || touched[0] != [3]int{1, 0, 1} {
+		t.Errorf("VoxelGrid.OverlapSphere() touched %v instead of only [1 0 1]", touched)
+	}
+
+	// a sphere near the pillar's corner doesn't reach the voxels around it
+	sphere = Sphere{Center: mgl.Vec3{3.2, 2.2, 3.2}, Radius: 0.5}
+	if vg.CollideVsSphere(&sphere) != NoIntersect {
+		t.Error("VoxelGrid.CollideVsSphere() indicated a sphere past the pillar's corner collided.")
+	}
+	sphere.Radius = 0.4
+	sphere.Center = mgl.Vec3{4.5, 2.3, 4.5}
+	if vg.CollideVsSphere(&sphere) != Intersect {
+		t.Error("VoxelGrid.CollideVsSphere() indicated a sphere on the pillar didn't collide.")
+	}
+
+	p := NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 1.5, 0})
+	if vg.CollideVsPlane(p) != Intersect {
+		t.Error("VoxelGrid.CollideVsPlane() indicated a plane didn't collide that should have.")
+	}
+	p = NewPlaneFromNormalAndPoint(mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 2.5, 0})
+	if vg.CollideVsPlane(p) != NoIntersect {
+		t.Error("VoxelGrid.CollideVsPlane() indicated a plane collided that should not have.")
+	}
+}
@@ -0,0 +1,464 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// DefaultLayer is the layer colliders are put on when added to a World.
+	DefaultLayer uint32 = 1
+
+	// AllLayers is a mask that matches every layer.
+	AllLayers uint32 = 0xFFFFFFFF
+)
+
+// worldBody is a collider owned by a World along with its layers.
+type worldBody struct {
+	collider Collider
+	layer    uint32
+	mask     uint32
+}
+
+// World owns a set of colliders behind Handles and uses a Broadphase to
+// find the pairs of them that collide and to answer ray, overlap and point
+// queries.
+//
+// Every collider is on one or more layers and has a mask of the layers it
+// collides with. A pair of colliders is only tested if each one's layer is
+// in the other's mask, and queries take a mask of the layers to test.
+//
+// Queries that return more than one result append to a slice passed in by
+// the caller and reuse the world's own scratch space, so once the slices
+// have grown they don't allocate. This also means a World shouldn't be
+// queried from more than one goroutine at a time.
+//
+// Step can spread its work across goroutines with SetWorkers. The colliders
+// are only read while it runs and the result is the same no matter how many
+// workers are used.
+type World struct {
+	broadphase Broadphase
+	bodies     map[Handle]*worldBody
+	order      []Handle
+	nextHandle Handle
+	pairs      []HandlePair
+	contacts   []HandlePair
+	queryBuf   []Handle
+	workers    int
+	pairHits   []bool
+
+	// scratch space for queries so that they don't allocate
+	queryRay    CollisionRay
+	querySphere Sphere
+}
+
+// NewWorld creates a new, empty World object that uses the broadphase to
+// find potential collisions. A nil broadphase uses a BruteForceBroadphase.
+func NewWorld(broadphase Broadphase) *World {
+	w := new(World)
+	if broadphase == nil {
+		broadphase = NewBruteForceBroadphase()
+	}
+	w.broadphase = broadphase
+	w.bodies = make(map[Handle]*worldBody)
+	return w
+}
+
+// Add puts the collider in the world on the DefaultLayer, colliding with
+// all layers, and returns its handle.
+func (w *World) Add(c Collider) Handle {
+	w.nextHandle++
+	h := w.nextHandle
+	w.bodies[h] = &worldBody{collider: c, layer: DefaultLayer, mask: AllLayers}
+	w.order = append(w.order, h)
+	w.broadphase.Insert(h, ColliderBounds(c))
+	return h
+}
+
+// Remove takes the collider out of the world. The handle won't be reused.
+func (w *World) Remove(h Handle) {
+	if _, okay := w.bodies[h]; !okay {
+		return
+	}
+	delete(w.bodies, h)
+	w.broadphase.Remove(h)
+	for i, oh := range w.order {
+		if oh == h {
+			w.order = append(w.order[:i], w.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Collider returns the collider for the handle. The second return value is
+// false if the handle isn't in the world.
+func (w *World) Collider(h Handle) (Collider, bool) {
+	body, okay := w.bodies[h]
+	if !okay {
+		return nil, false
+	}
+	return body.collider, true
+}
+
+// Handles appends the handles of all of the colliders in the world to
+// result in the order they were added and returns the result.
+func (w *World) Handles(result []Handle) []Handle {
+	return append(result, w.order...)
+}
+
+// Broadphase returns the broadphase the world uses.
+func (w *World) Broadphase() Broadphase {
+	return w.broadphase
+}
+
+// Contacts returns the colliding pairs found by the last Step.
+func (w *World) Contacts() []HandlePair {
+	return w.contacts
+}
+
+// SetLayers changes the layers the collider is on and the mask of layers
+// that it collides with.
+func (w *World) SetLayers(h Handle, layer, mask uint32) {
+	if body, okay := w.bodies[h]; okay {
+		body.layer = layer
+		body.mask = mask
+	}
+}
+
+// SetOffset changes the offset of the collider and updates the broadphase.
+func (w *World) SetOffset(h Handle, offset *mgl.Vec3) {
+	if body, okay := w.bodies[h]; okay {
+		body.collider.SetOffset(offset)
+		w.broadphase.Update(h, ColliderBounds(body.collider))
+	}
+}
+
+// SetOffset3f changes the offset of the collider and updates the broadphase.
+func (w *World) SetOffset3f(h Handle, x, y, z float32) {
+	if body, okay := w.bodies[h]; okay {
+		body.collider.SetOffset3f(x, y, z)
+		w.broadphase.Update(h, ColliderBounds(body.collider))
+	}
+}
+
+// Update refreshes the broadphase after the collider was changed directly,
+// such as by resizing it or changing its orientation.
+func (w *World) Update(h Handle) {
+	if body, okay := w.bodies[h]; okay {
+		w.broadphase.Update(h, ColliderBounds(body.collider))
+	}
+}
+
+// layersMatch returns true if the two bodies are on layers the other one
+// collides with.
+func layersMatch(b1, b2 *worldBody) bool {
+	return b1.layer&b2.mask != 0 && b2.layer&b1.mask != 0
+}
+
+// SetWorkers sets how many goroutines Step uses to find pairs with the
+// broadphase, if it is a ParallelBroadphase, and to test them. A count of
+// one or less does all of the work on the calling goroutine. Starting the
+// goroutines allocates a little memory every step.
+func (w *World) SetWorkers(workers int) {
+	w.workers = workers
+}
+
+// Workers returns how many goroutines Step uses.
+func (w *World) Workers() int {
+	if w.workers < 1 {
+		return 1
+	}
+	return w.workers
+}
+
+// testPair returns true if the colliders of the pair are in the world, on
+// layers that collide with each other and colliding.
+func (w *World) testPair(pair HandlePair) bool {
+	b1, b2 := w.bodies[pair.A], w.bodies[pair.B]
+	if b1 == nil || b2 == nil || !layersMatch(b1, b2) {
+		return false
+	}
+	return collidePair(b1.collider, b2.collider) == Intersect
+}
+
+// Step finds every pair of colliders in the world that are colliding and
+// returns them sorted by handle. The returned slice is reused by the
+// next step.
+func (w *World) Step() []HandlePair {
+	w.contacts = w.contacts[:0]
+	if w.workers <= 1 {
+		w.pairs = w.broadphase.Pairs(w.pairs[:0])
+		for _, pair := range w.pairs {
+			if w.testPair(pair) {
+				w.contacts = append(w.contacts, pair)
+			}
+		}
+		sortHandlePairs(&w.contacts)
+		return w.contacts
+	}
+
+	if pb, okay := w.broadphase.(ParallelBroadphase); okay {
+		w.pairs = pb.ParallelPairs(w.workers, w.pairs[:0])
+	} else {
+		w.pairs = w.broadphase.Pairs(w.pairs[:0])
+	}
+
+	// each pair's result has its own slot so the workers never share memory
+	n := len(w.pairs)
+	if cap(w.pairHits) < n {
+		w.pairHits = make([]bool, n)
+	}
+	w.pairHits = w.pairHits[:n]
+	chunks := chunkCount(n, w.workers)
+	runParallel(w.workers, chunks, func(c int) {
+		start, end := chunkRange(n, chunks, c)
+		for i := start; i < end; i++ {
+			w.pairHits[i] = w.testPair(w.pairs[i])
+		}
+	})
+	for i, pair := range w.pairs {
+		if w.pairHits[i] {
+			w.contacts = append(w.contacts, pair)
+		}
+	}
+	sortHandlePairs(&w.contacts)
+	return w.contacts
+}
+
+// candidates returns the handles of the colliders on the layers in mask
+// that pass the tag filters and might overlap the bounds, sorted by handle.
+// The returned slice is reused by the next query.
+func (w *World) candidates(b Bounds, mask uint32, filters []TagFilter) []Handle {
+	w.queryBuf = w.broadphase.Query(b, w.queryBuf[:0])
+	handles := w.queryBuf
+	n := 0
+	for _, h := range handles {
+		body, okay := w.bodies[h]
+		if okay && body.layer&mask != 0 && passesTagFilters(body.collider, filters) {
+			handles[n] = h
+			n++
+		}
+	}
+	w.queryBuf = handles[:n]
+	sortHandles(&w.queryBuf)
+	return w.queryBuf
+}
+
+// rayCandidates returns the handles of the colliders on the layers in mask
+// that pass the tag filters and that the ray might hit.
+func (w *World) rayCandidates(ray *CollisionRay, mask uint32, filters []TagFilter) []Handle {
+	b := infiniteBounds
+	if ray.MaxDistance > 0 {
+		b = boundsFromPoints(ray.Origin, ray.Origin.Add(ray.direction.Mul(ray.MaxDistance)))
+	}
+	return w.candidates(b, mask, filters)
+}
+
+// RayCastAll casts the ray against the colliders on the layers in mask,
+// appends every hit to result sorted by distance, closest first, and returns
+// the result. Hits at the same distance are sorted by handle and have their
+// Handle set and an Index of -1. Colliders whose TagSet doesn't pass all of
+// the filters are skipped.
+func (w *World) RayCastAll(ray *CollisionRay, mask uint32, result []RayHit, filters ...TagFilter) []RayHit {
+	start := len(result)
+	for _, h := range w.rayCandidates(ray, mask, filters) {
+		c := w.bodies[h].collider
+		hit, dist := castRay(ray, c)
+		if hit == Intersect {
+			result = append(result, newRayHit(c, -1, h, dist))
+		}
+	}
+	sortRayHits(result[start:])
+	return result
+}
+
+// RayCast casts the ray against the colliders on the layers in mask and
+// returns only the closest hit. Colliders whose TagSet doesn't pass all of
+// the filters are skipped. The second return value is false if nothing
+// was hit.
+func (w *World) RayCast(ray *CollisionRay, mask uint32, filters ...TagFilter) (RayHit, bool) {
+	var closest RayHit
+	found := false
+
+	bounded := &w.queryRay
+	*bounded = *ray
+	for _, h := range w.rayCandidates(ray, mask, filters) {
+		c := w.bodies[h].collider
+		result, dist := castRay(bounded, c)
+		if result == NoIntersect {
+			continue
+		}
+		if !found || dist < closest.Distance {
+			closest = newRayHit(c, -1, h, dist)
+			found = true
+			if dist <= 0.0 {
+				break
+			}
+			bounded.MaxDistance = dist
+		}
+	}
+
+	return closest, found
+}
+
+// castFirst sweeps something with the bounds extent around the ray's origin
+// along the ray against the colliders on the layers in mask that pass the
+// tag filters and returns the first hit. The cast function is given a copy
+// of the ray that is shortened as closer hits are found.
+func (w *World) castFirst(ray *CollisionRay, extent Bounds, mask uint32, filters []TagFilter, cast func(bounded *CollisionRay, c Collider) (int, CastHit)) (CastHit, bool) {
+	var closest CastHit
+	found := false
+
+	b := infiniteBounds
+	if ray.MaxDistance > 0 {
+		b = sweptBounds(ray, extent, 0)
+	}
+	bounded := &w.queryRay
+	*bounded = *ray
+	for _, h := range w.candidates(b, mask, filters) {
+		result, hit := cast(bounded, w.bodies[h].collider)
+		if result == NoIntersect {
+			continue
+		}
+		if !found || hit.Distance < closest.Distance {
+			closest = hit
+			closest.Handle = h
+			found = true
+			if hit.Distance <= 0.0 {
+				break
+			}
+			bounded.MaxDistance = hit.Distance
+		}
+	}
+
+	return closest, found
+}
+
+// SphereCast sweeps a sphere of the radius centered on the ray's origin
+// along the ray against the colliders on the layers in mask and returns the
+// first one it touches. Colliders whose TagSet doesn't pass all of the
+// filters are skipped. The second return value is false if nothing was hit.
+func (w *World) SphereCast(ray *CollisionRay, radius float32, mask uint32, filters ...TagFilter) (CastHit, bool) {
+	extent := Bounds{
+		Min: mgl.Vec3{-radius, -radius, -radius},
+		Max: mgl.Vec3{radius, radius, radius},
+	}
+	return w.castFirst(ray, extent, mask, filters, func(bounded *CollisionRay, c Collider) (int, CastHit) {
+		return SphereCast(bounded, radius, c)
+	})
+}
+
+// BoxCast sweeps the box along the ray against the colliders on the layers
+// in mask and returns the first one it touches. The box is placed with its
+// Offset at the ray's origin. Colliders whose TagSet doesn't pass all of the
+// filters are skipped. The second return value is false if nothing was hit.
+func (w *World) BoxCast(ray *CollisionRay, b *AABBox, mask uint32, filters ...TagFilter) (CastHit, bool) {
+	shape, extent := boxCastShape(ray, b)
+	return w.castFirst(ray, extent, mask, filters, func(bounded *CollisionRay, c Collider) (int, CastHit) {
+		return castShape(bounded, shape, extent, c)
+	})
+}
+
+// OBBoxCast sweeps the oriented box along the ray against the colliders on
+// the layers in mask and returns the first one it touches. The box is placed
+// with its Offset at the ray's origin. Colliders whose TagSet doesn't pass
+// all of the filters are skipped. The second return value is false if
+// nothing was hit.
+func (w *World) OBBoxCast(ray *CollisionRay, obb *OBBox, mask uint32, filters ...TagFilter) (CastHit, bool) {
+	shape, extent := obbCastShape(ray, obb)
+	return w.castFirst(ray, extent, mask, filters, func(bounded *CollisionRay, c Collider) (int, CastHit) {
+		return castShape(bounded, shape, extent, c)
+	})
+}
+
+// QueryOverlap appends the handles of the colliders on the layers in mask
+// that pass the tag filters and collide with c to result and returns the
+// result. The collider c doesn't need to be in the world.
+func (w *World) QueryOverlap(c Collider, mask uint32, result []Handle, filters ...TagFilter) []Handle {
+	for _, h := range w.candidates(ColliderBounds(c), mask, filters) {
+		other := w.bodies[h].collider
+		if other == c {
+			continue
+		}
+		if collidePair(c, other) == Intersect {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// QueryPoint appends the handles of the colliders on the layers in mask
+// that pass the tag filters and contain the point to result and returns
+// the result.
+func (w *World) QueryPoint(p mgl.Vec3, mask uint32, result []Handle, filters ...TagFilter) []Handle {
+	point := &w.querySphere
+	*point = Sphere{Center: p}
+	for _, h := range w.candidates(Bounds{Min: p, Max: p}, mask, filters) {
+		if w.bodies[h].collider.CollideVsSphere(point) == Intersect {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// nearestBounds returns the bounds that colliders within maxDist of the
+// point have to overlap. A maxDist of zero or less doesn't limit the distance.
+func nearestBounds(p mgl.Vec3, maxDist float32) Bounds {
+	if maxDist <= 0 {
+		return infiniteBounds
+	}
+	r := mgl.Vec3{maxDist, maxDist, maxDist}
+	return Bounds{Min: p.Sub(r), Max: p.Add(r)}
+}
+
+// QueryNearest returns the collider on the layers in mask that is closest
+// to the point and no further away than maxDistance. A maxDistance of zero
+// or less doesn't limit the distance. The hit has its Handle set and an
+// Index of -1. Colliders whose TagSet doesn't pass all of the filters are
+// skipped. The second return value is false if nothing was found.
+func (w *World) QueryNearest(p mgl.Vec3, maxDistance float32, mask uint32, filters ...TagFilter) (NearestHit, bool) {
+	var buf [1]NearestHit
+	hits := buf[:0]
+	for _, h := range w.candidates(nearestBounds(p, maxDistance), mask, filters) {
+		hits = addNearest(p, w.bodies[h].collider, -1, h, 1, maxDistance, hits, 0)
+	}
+	if len(hits) == 0 {
+		return NearestHit{}, false
+	}
+	return hits[0], true
+}
+
+// QueryNearestK appends up to k of the colliders on the layers in mask that
+// are closest to the point and no further away than maxDistance to result,
+// sorted by distance, and returns the result. A maxDistance of zero or less
+// doesn't limit the distance. Colliders at the same distance are sorted by
+// handle. Colliders whose TagSet doesn't pass all of the filters are skipped.
+func (w *World) QueryNearestK(p mgl.Vec3, k int, maxDistance float32, mask uint32, result []NearestHit, filters ...TagFilter) []NearestHit {
+	if k <= 0 {
+		return result
+	}
+	start := len(result)
+	for _, h := range w.candidates(nearestBounds(p, maxDistance), mask, filters) {
+		result = addNearest(p, w.bodies[h].collider, -1, h, k, maxDistance, result, start)
+	}
+	return result
+}
+
+// QueryRadius appends all of the colliders on the layers in mask whose
+// surface is no further than radius from the point to result, sorted by
+// distance, and returns the result. Colliders at the same distance are
+// sorted by handle. Colliders whose TagSet doesn't pass all of the filters
+// are skipped.
+func (w *World) QueryRadius(p mgl.Vec3, radius float32, mask uint32, result []NearestHit, filters ...TagFilter) []NearestHit {
+	if radius < 0 {
+		return result
+	}
+	radius = max32(radius, rayEpsilon)
+	start := len(result)
+	for _, h := range w.candidates(nearestBounds(p, radius), mask, filters) {
+		result = addNearest(p, w.bodies[h].collider, -1, h, 0, radius, result, start)
+	}
+	return result
+}
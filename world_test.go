@@ -0,0 +1,213 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package glider
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestWorldBox makes a unit box centered on the position.
+func newTestWorldBox(x, y, z float32) *AABBox {
+	b := NewAABBox()
+	b.Min = mgl.Vec3{-0.5, -0.5, -0.5}
+	b.Max = mgl.Vec3{0.5, 0.5, 0.5}
+	b.SetOffset3f(x, y, z)
+	return b
+}
+
+func TestWorldStep(t *testing.T) {
+	for _, bp := range []Broadphase{NewBruteForceBroadphase(), NewSweepAndPruneBroadphase()} {
+		w := NewWorld(bp)
+		h1 := w.Add(newTestWorldBox(0, 0, 0))
+		h2 := w.Add(newTestWorldBox(0.9, 0, 0))
+		h3 := w.Add(newTestWorldBox(5, 0, 0))
+
+		pairs := w.Step()
+		if len(pairs) != 1 || pairs[0] != (HandlePair{h1, h2}) {
+			t.Errorf("World.Step() returned %v instead of only the first two boxes", pairs)
+		}
+
+		// move the third box onto the first
+		w.SetOffset3f(h3, -0.5, 0.5, 0.0)
+		pairs = w.Step()
+		if len(pairs) != 2 || pairs[0] != (HandlePair{h1, h2}) || pairs[1] != (HandlePair{h1, h3}) {
+			t.Errorf("World.Step() returned %v after moving a box", pairs)
+		}
+
+		// put the third box on a layer the first doesn't collide with
+		w.SetLayers(h1, DefaultLayer, DefaultLayer)
+		w.SetLayers(h3, 2, AllLayers)
+		pairs = w.Step()
+		if len(pairs) != 1 || pairs[0] != (HandlePair{h1, h2}) {
+			t.Errorf("World.Step() returned %v after changing layers", pairs)
+		}
+
+		w.Remove(h2)
+		if pairs = w.Step(); len(pairs) != 0 {
+			t.Errorf("World.Step() returned %v after removing a box", pairs)
+		}
+		if _, okay := w.Collider(h2); okay {
+			t.Error("World.Collider() returned a collider that was removed.")
+		}
+	}
+}
+
+func TestWorldQueries(t *testing.T) {
+	w := NewWorld(NewSweepAndPruneBroadphase())
+	near := w.Add(newTestWorldBox(0, 0, -5))
+	far := w.Add(newTestWorldBox(0, 0, -10))
+	s := NewSphere()
+	s.Radius = 1.0
+	s.SetOffset3f(3.0, 0.0, -5.0)
+	sphere := w.Add(s)
+	w.SetLayers(far, 2, AllLayers)
+
+	ray, _ := NewCollisionRay(mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 0, -1})
+	hits := w.RayCastAll(ray, AllLayers, nil)
+	if len(hits) != 2 || hits[0].Handle != near || hits[1].Handle != far {
+		t.Errorf("World.RayCastAll() returned the wrong hits: %v", hits)
+	}
+	if hit, okay := w.RayCast(ray, 2); !okay || hit.Handle != far || !mgl.FloatEqual(hit.Distance, 9.5) {
+		t.Errorf("World.RayCast() didn't filter by layer: %v %v", hit, okay)
+	}
+	ray.MaxDistance = 4.0
+	if _, okay := w.RayCast(ray, AllLayers); okay {
+		t.Error("World.RayCast() hit beyond the ray's MaxDistance.")
+	}
+
+	probe := newTestWorldBox(1.5, 0, -5)
+	probe.Min[0] = -1.1
+	overlaps := w.QueryOverlap(probe, AllLayers, nil)
+	if len(overlaps) != 2 || overlaps[0] != near || overlaps[1] != sphere {
+		t.Errorf("World.QueryOverlap() returned %v instead of the near box and sphere", overlaps)
+	}
+
+	points := w.QueryPoint(mgl.Vec3{3.5, 0.0, -5.0}, AllLayers, nil)
+	if len(points) != 1 || points[0] != sphere {
+		t.Errorf("World.QueryPoint() returned %v instead of the sphere", points)
+	}
+	if points = w.QueryPoint(mgl.Vec3{0, 0, -10}, DefaultLayer, points[:0]); len(points) != 0 {
+		t.Errorf("World.QueryPoint() didn't filter by layer: %v", points)
+	}
+}
+
+func TestWorldStepConvexShapes(t *testing.T) {
+	newEllipsoid := func(radii, offset mgl.Vec3) *Ellipsoid {
+		e := NewEllipsoid()
+		e.Radii = radii
+		e.SetOffset(&offset)
+		return e
+	}
+
+	for _, bp := range []Broadphase{NewBruteForceBroadphase(), NewSweepAndPruneBroadphase()} {
+		w := NewWorld(bp)
+		long := mgl.Vec3{2.0, 0.5, 0.5}
+		h1 := w.Add(newEllipsoid(long, mgl.Vec3{0, 0, 0}))
+		h2 := w.Add(newEllipsoid(long, mgl.Vec3{3.5, 0, 0}))
+
+		// the bounds overlap the first ellipsoid but the shapes don't touch
+		w.Add(newEllipsoid(mgl.Vec3{1, 1, 1}, mgl.Vec3{-2.3, 1.3, 0}))
+
+		floor := newTestQuadMesh()
+		floor.SetOffset3f(0.0, -0.45, 0.0)
+		h4 := w.Add(floor)
+
+		pairs := w.Step()
+		if len(pairs) != 2 || pairs[0] != (HandlePair{h1, h2}) || pairs[1] != (HandlePair{h1, h4}) {
+			t.Errorf("World.Step() returned %v instead of the overlapping ellipsoids and the floor", pairs)
+		}
+
+		probe := newEllipsoid(mgl.Vec3{0.3, 0.3, 0.3}, mgl.Vec3{1.75, 0, 0})
+		overlaps := w.QueryOverlap(probe, AllLayers, nil)
+		if len(overlaps) != 2 || overlaps[0] != h1 || overlaps[1] != h2 {
+			t.Errorf("World.QueryOverlap() returned %v instead of the two long ellipsoids", overlaps)
+		}
+	}
+}
+
+func TestWorldQueryAllocs(t *testing.T) {
+	w := NewWorld(NewSweepAndPruneBroadphase())
+	for i := 0; i < 20; i++ {
+		w.Add(newTestWorldBox(float32(i)*0.9, 0, 0))
+		s := NewSphere()
+		s.Radius = 0.5
+		s.SetOffset3f(float32(i)*0.9, 1.0, 0.0)
+		w.Add(s)
+	}
+	probe := newTestWorldBox(5, 0, 0)
+	ray, _ := NewCollisionRay(mgl.Vec3{-5, 0, 0}, mgl.Vec3{1, 0, 0})
+	visible := WithoutTags(1)
+
+	// the first pass grows the buffers that later passes reuse
+	var handles []Handle
+	var hits []RayHit
+	var nearest []NearestHit
+	query := func() {
+		w.Step()
+		handles = w.QueryOverlap(probe, AllLayers, handles[:0], visible)
+		handles = w.QueryPoint(mgl.Vec3{5, 0, 0}, AllLayers, handles[:0])
+		hits = w.RayCastAll(ray, AllLayers, hits[:0])
+		nearest = w.QueryNearestK(mgl.Vec3{5, 2, 0}, 3, 0, AllLayers, nearest[:0])
+		nearest = w.QueryRadius(mgl.Vec3{5, 2, 0}, 1.0, AllLayers, nearest[:0])
+		w.RayCast(ray, AllLayers)
+	}
+	query()
+	if allocs := testing.AllocsPerRun(100, query); allocs != 0 {
+		t.Errorf("World queries allocated %v times once their buffers had grown", allocs)
+	}
+}
+
+func TestWorldStepParallel(t *testing.T) {
+	worlds := []*World{
+		NewWorld(NewBruteForceBroadphase()),
+		NewWorld(NewSweepAndPruneBroadphase()),
+	}
+	boxes, _ := newTestSoABoxes(300)
+	for _, w := range worlds {
+		for i, b := range boxes {
+			h := w.Add(b)
+			if i%3 == 0 {
+				s := NewSphere()
+				s.Radius = 0.75
+				s.Offset = b.Offset
+				w.Add(s)
+			}
+			if i%7 == 0 {
+				w.SetLayers(h, 2, 2)
+			}
+		}
+	}
+
+	for _, w := range worlds {
+		expected := append([]HandlePair(nil), w.Step()...)
+		if len(expected) == 0 {
+			t.Fatal("World.Step() didn't find any contacts to compare")
+		}
+		for _, workers := range []int{2, 4, 16} {
+			w.SetWorkers(workers)
+			if w.Workers() != workers {
+				t.Errorf("World.Workers() returned %d instead of %d", w.Workers(), workers)
+			}
+			for run := 0; run < 3; run++ {
+				contacts := w.Step()
+				if len(contacts) != len(expected) {
+					t.Errorf("World.Step() with %d workers returned %d contacts instead of %d", workers, len(contacts), len(expected))
+					break
+				}
+				for i := range contacts {
+					if contacts[i] != expected[i] {
+						t.Errorf("World.Step() with %d workers returned %v at %d instead of %v", workers, contacts[i], i, expected[i])
+						break
+					}
+				}
+			}
+		}
+		w.SetWorkers(0)
+		if w.Workers() != 1 {
+			t.Errorf("World.Workers() returned %d instead of 1 after setting 0", w.Workers())
+		}
+	}
+}